@@ -3,6 +3,7 @@ module github.com/kilnfi/near-validator-watcher
 go 1.20
 
 require (
+	github.com/BurntSushi/toml v1.3.2
 	github.com/avast/retry-go/v4 v4.5.0
 	github.com/fatih/color v1.15.0
 	github.com/prometheus/client_golang v1.16.0
@@ -11,6 +12,7 @@ require (
 	github.com/stretchr/testify v1.8.4
 	github.com/urfave/cli/v2 v2.25.7
 	golang.org/x/sync v0.3.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -32,5 +34,4 @@ require (
 	github.com/xrash/smetrics v0.0.0-20201216005158-039620a65673 // indirect
 	golang.org/x/sys v0.12.0 // indirect
 	google.golang.org/protobuf v1.31.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,175 @@
+package alert
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type Status string
+
+const (
+	StatusFiring   Status = "firing"
+	StatusResolved Status = "resolved"
+)
+
+type Severity string
+
+const (
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// Alert is a notification sent when a condition starts or stops being met.
+type Alert struct {
+	// Name identifies the condition (eg. validator_uptime_low)
+	Name string `json:"name"`
+	// Key deduplicates alerts of the same condition (eg. name + account id)
+	Key      string            `json:"key"`
+	Status   Status            `json:"status"`
+	Severity Severity          `json:"severity"`
+	Summary  string            `json:"summary"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	StartsAt time.Time         `json:"starts_at"`
+	EndsAt   *time.Time        `json:"ends_at,omitempty"`
+}
+
+// Notifier delivers alerts to an external service.
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// Manager keeps track of firing alerts so that notifications are only sent
+// when an alert starts firing, is resolved, or keeps firing longer than the
+// repeat interval.
+type Manager struct {
+	notifiers      []Notifier
+	repeatInterval time.Duration
+
+	mu       sync.Mutex
+	firing   map[string]firingAlert
+	outgoing chan Alert
+}
+
+type firingAlert struct {
+	alert    Alert
+	notified time.Time
+}
+
+type Option func(*Manager)
+
+// WithRepeatInterval sends firing alerts again after the given interval.
+func WithRepeatInterval(interval time.Duration) Option {
+	return func(m *Manager) {
+		m.repeatInterval = interval
+	}
+}
+
+func NewManager(notifiers []Notifier, options ...Option) *Manager {
+	manager := &Manager{
+		notifiers: notifiers,
+		firing:    make(map[string]firingAlert),
+		outgoing:  make(chan Alert, 100),
+	}
+
+	for _, option := range options {
+		option(manager)
+	}
+
+	return manager
+}
+
+// Fire marks the alert as firing, a notification is only sent the first time.
+func (m *Manager) Fire(alert Alert) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	current, ok := m.firing[alert.Key]
+	if ok && (m.repeatInterval == 0 || now.Sub(current.notified) < m.repeatInterval) {
+		return
+	}
+	if ok {
+		alert.StartsAt = current.alert.StartsAt
+	} else {
+		alert.StartsAt = now
+	}
+
+	alert.Status = StatusFiring
+	alert.EndsAt = nil
+	m.firing[alert.Key] = firingAlert{alert: alert, notified: now}
+	m.send(alert)
+}
+
+// Resolve marks the alert as resolved if it was firing.
+func (m *Manager) Resolve(key string) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.firing[key]
+	if !ok {
+		return
+	}
+	delete(m.firing, key)
+
+	now := time.Now()
+	alert := current.alert
+	alert.Status = StatusResolved
+	alert.EndsAt = &now
+	m.send(alert)
+}
+
+// Firing returns the alerts currently firing.
+func (m *Manager) Firing() []Alert {
+	if m == nil {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	alerts := make([]Alert, 0, len(m.firing))
+	for _, f := range m.firing {
+		alerts = append(alerts, f.alert)
+	}
+	return alerts
+}
+
+func (m *Manager) send(alert Alert) {
+	select {
+	case m.outgoing <- alert:
+	default:
+		logrus.WithField("alert", alert.Key).Error("alert queue is full, dropping notification")
+	}
+}
+
+// Start delivers notifications until the context is cancelled.
+func (m *Manager) Start(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case alert := <-m.outgoing:
+			logrus.WithFields(logrus.Fields{
+				"alert":  alert.Key,
+				"status": alert.Status,
+			}).Info(alert.Summary)
+
+			for _, notifier := range m.notifiers {
+				if err := notifier.Notify(ctx, alert); err != nil {
+					logrus.WithError(err).WithField("alert", alert.Key).Error("failed to send alert")
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receive(t *testing.T, m *Manager) Alert {
+	select {
+	case alert := <-m.outgoing:
+		return alert
+	case <-time.After(time.Second):
+		t.Fatal("no alert sent")
+	}
+	return Alert{}
+}
+
+func TestManager(t *testing.T) {
+	t.Run("Deduplicate and resolve", func(t *testing.T) {
+		m := NewManager(nil)
+
+		m.Fire(Alert{Name: "node_syncing", Key: "node_syncing", Summary: "syncing"})
+		alert := receive(t, m)
+		assert.Equal(t, StatusFiring, alert.Status)
+		assert.False(t, alert.StartsAt.IsZero())
+		assert.Nil(t, alert.EndsAt)
+
+		// Still firing, nothing sent
+		m.Fire(Alert{Name: "node_syncing", Key: "node_syncing", Summary: "syncing"})
+		assert.Len(t, m.outgoing, 0)
+		assert.Len(t, m.Firing(), 1)
+
+		m.Resolve("node_syncing")
+		alert = receive(t, m)
+		assert.Equal(t, StatusResolved, alert.Status)
+		assert.NotNil(t, alert.EndsAt)
+		assert.Len(t, m.Firing(), 0)
+
+		// Already resolved, nothing sent
+		m.Resolve("node_syncing")
+		assert.Len(t, m.outgoing, 0)
+	})
+
+	t.Run("Repeat interval", func(t *testing.T) {
+		m := NewManager(nil, WithRepeatInterval(time.Nanosecond))
+
+		m.Fire(Alert{Name: "node_syncing", Key: "node_syncing"})
+		first := receive(t, m)
+
+		time.Sleep(time.Millisecond)
+		m.Fire(Alert{Name: "node_syncing", Key: "node_syncing"})
+		second := receive(t, m)
+		assert.Equal(t, first.StartsAt, second.StartsAt)
+	})
+
+	t.Run("Nil manager", func(t *testing.T) {
+		var m *Manager
+		m.Fire(Alert{Key: "foo"})
+		m.Resolve("foo")
+		assert.Empty(t, m.Firing())
+	})
+}
+
+func TestWebhook(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		requests atomic.Int32
+		received Alert
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first attempt
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &received)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL, WithRetry(3, time.Millisecond))
+	err := webhook.Notify(ctx, Alert{
+		Name:     "validator_uptime_low",
+		Key:      "validator_uptime_low/kiln.poolv1.near",
+		Status:   StatusFiring,
+		Severity: SeverityWarning,
+		Labels:   map[string]string{"account_id": "kiln.poolv1.near"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(2), requests.Load())
+	assert.Equal(t, "validator_uptime_low/kiln.poolv1.near", received.Key)
+	assert.Equal(t, StatusFiring, received.Status)
+	assert.Equal(t, "kiln.poolv1.near", received.Labels["account_id"])
+
+	t.Run("Client errors are not retried", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		err := NewWebhook(server.URL, WithRetry(3, time.Millisecond)).Notify(ctx, Alert{})
+		require.Error(t, err)
+		assert.Equal(t, int32(1), attempts.Load())
+	})
+}
@@ -0,0 +1,97 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+)
+
+// Webhook posts alerts as JSON to an HTTP endpoint.
+type Webhook struct {
+	url        string
+	httpClient *http.Client
+	attempts   uint
+	delay      time.Duration
+}
+
+type WebhookOption func(*Webhook)
+
+func WithHTTPClient(httpClient *http.Client) WebhookOption {
+	return func(w *Webhook) {
+		w.httpClient = httpClient
+	}
+}
+
+// WithRetry sets how many times a notification is attempted and the initial
+// delay between attempts.
+func WithRetry(attempts uint, delay time.Duration) WebhookOption {
+	return func(w *Webhook) {
+		w.attempts = attempts
+		w.delay = delay
+	}
+}
+
+func NewWebhook(url string, options ...WebhookOption) *Webhook {
+	webhook := &Webhook{
+		url:        url,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		attempts:   5,
+		delay:      1 * time.Second,
+	}
+
+	for _, option := range options {
+		option(webhook)
+	}
+
+	return webhook
+}
+
+func (w *Webhook) Notify(ctx context.Context, alert Alert) error {
+	return w.Send(ctx, alert)
+}
+
+// Send posts any value encoded as JSON, retrying on server errors.
+func (w *Webhook) Send(ctx context.Context, body interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	return retry.Do(func() error {
+		return w.post(ctx, payload)
+	},
+		retry.Context(ctx),
+		retry.Attempts(w.attempts),
+		retry.Delay(w.delay),
+		retry.LastErrorOnly(true),
+	)
+}
+
+func (w *Webhook) post(ctx context.Context, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", w.url, bytes.NewBuffer(payload))
+	if err != nil {
+		return retry.Unrecoverable(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		err := fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return retry.Unrecoverable(err)
+		}
+		return err
+	}
+
+	return nil
+}
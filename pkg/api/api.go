@@ -0,0 +1,230 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/history"
+	"github.com/kilnfi/near-validator-watcher/pkg/watcher"
+)
+
+// Prefix is the path the API is served under.
+const Prefix = "/api/v1/"
+
+// Source provides the data of a watched network.
+type Source interface {
+	Snapshot() *watcher.Snapshot
+	History() *history.Store
+}
+
+// API serves the last collected data of the watched networks as JSON.
+type API struct {
+	sources map[string]Source
+	mux     *http.ServeMux
+}
+
+func New(sources map[string]Source) *API {
+	api := &API{
+		sources: sources,
+		mux:     http.NewServeMux(),
+	}
+
+	api.mux.HandleFunc(Prefix+"status", api.handleStatus)
+	api.mux.HandleFunc(Prefix+"epoch", api.handleEpoch)
+	api.mux.HandleFunc(Prefix+"epochs", api.handleEpochs)
+	api.mux.HandleFunc(Prefix+"epochs/", api.handleEpochs)
+	api.mux.HandleFunc(Prefix+"validators", api.handleValidators)
+	api.mux.HandleFunc(Prefix+"validators/", api.handleValidators)
+
+	return api
+}
+
+func (a *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	a.mux.ServeHTTP(w, r)
+}
+
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+// source returns the network given as network query parameter, which can be
+// omitted when a single network is watched.
+func (a *API) source(r *http.Request) (string, Source, error) {
+	network := r.URL.Query().Get("network")
+	if network == "" {
+		if len(a.sources) != 1 {
+			return "", nil, &apiError{http.StatusBadRequest, fmt.Sprintf("network query parameter is required (%s)", strings.Join(a.networks(), ", "))}
+		}
+		for name := range a.sources {
+			network = name
+		}
+	}
+
+	source, ok := a.sources[network]
+	if !ok {
+		return "", nil, &apiError{http.StatusNotFound, fmt.Sprintf("unknown network %q", network)}
+	}
+	return network, source, nil
+}
+
+// snapshot returns the last data collected for the requested network.
+func (a *API) snapshot(r *http.Request) (*watcher.Snapshot, error) {
+	network, source, err := a.source(r)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := source.Snapshot()
+	if snapshot == nil {
+		return nil, &apiError{http.StatusServiceUnavailable, fmt.Sprintf("no data collected yet for network %s", network)}
+	}
+	return snapshot, nil
+}
+
+func (a *API) networks() []string {
+	networks := make([]string, 0, len(a.sources))
+	for network := range a.sources {
+		networks = append(networks, network)
+	}
+	sort.Strings(networks)
+	return networks
+}
+
+func (a *API) handleStatus(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := a.snapshot(r)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newStatus(snapshot))
+}
+
+func (a *API) handleEpoch(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := a.snapshot(r)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newEpoch(snapshot))
+}
+
+func (a *API) handleValidators(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := a.snapshot(r)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+
+	validators := newValidators(snapshot)
+
+	accountID := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, Prefix+"validators"), "/")
+	if accountID == "" {
+		if r.URL.Query().Get("tracked") == "true" {
+			tracked := make([]Validator, 0)
+			for _, v := range validators {
+				if v.Tracked {
+					tracked = append(tracked, v)
+				}
+			}
+			validators = tracked
+		}
+
+		writeJSON(w, http.StatusOK, ValidatorList{
+			Network:     snapshot.Network,
+			EpochHeight: snapshot.Validators.EpochHeight,
+			Validators:  validators,
+		})
+		return
+	}
+
+	for _, v := range validators {
+		if v.AccountID == accountID {
+			writeJSON(w, http.StatusOK, v)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("unknown validator %q", accountID))
+}
+
+func (a *API) handleEpochs(w http.ResponseWriter, r *http.Request) {
+	network, source, err := a.source(r)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+
+	store := source.History()
+	if store == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("epoch history is disabled for network %s", network))
+		return
+	}
+
+	param := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, Prefix+"epochs"), "/")
+	if param == "" {
+		heights, err := store.Heights()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, EpochList{Network: network, EpochHeights: heights})
+		return
+	}
+
+	epochHeight, err := strconv.ParseInt(param, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid epoch height %q", param))
+		return
+	}
+
+	epoch, err := store.Get(epochHeight)
+	if errors.Is(err, history.ErrNotFound) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("epoch %d not recorded", epochHeight))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var trackedAccounts []string
+	if snapshot := source.Snapshot(); snapshot != nil {
+		trackedAccounts = snapshot.TrackedAccounts
+	}
+	writeJSON(w, http.StatusOK, newEpochRecord(network, epoch, trackedAccounts))
+}
+
+func writeErr(w http.ResponseWriter, err error) {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		writeError(w, apiErr.status, apiErr.message)
+		return
+	}
+	writeError(w, http.StatusInternalServerError, err.Error())
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/history"
+	"github.com/kilnfi/near-validator-watcher/pkg/watcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type source struct {
+	snapshot *watcher.Snapshot
+	history  *history.Store
+}
+
+func (s source) Snapshot() *watcher.Snapshot { return s.snapshot }
+func (s source) History() *history.Store     { return s.history }
+
+func get(t *testing.T, api *API, path string, body interface{}) int {
+	rec := httptest.NewRecorder()
+	api.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	if body != nil {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), body), rec.Body.String())
+	}
+	return rec.Code
+}
+
+func TestAPI(t *testing.T) {
+	snapshot := &watcher.Snapshot{
+		Network:         "testnet",
+		TrackedAccounts: []string{"kiln.pool.f863973.m0"},
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"chain_id": "testnet",
+		"sync_info": {"latest_block_height": 1250, "syncing": false}
+	}`), &snapshot.Status))
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"epoch_height": 10,
+		"epoch_start_height": 1000,
+		"current_validators": [
+			{"account_id": "node1", "stake": "1000", "num_produced_blocks": 10, "num_expected_blocks": 10},
+			{"account_id": "kiln.pool.f863973.m0", "stake": "2000", "num_produced_blocks": 9, "num_expected_blocks": 10}
+		],
+		"next_validators": [
+			{"account_id": "kiln.pool.f863973.m0", "stake": "2100"},
+			{"account_id": "node2", "stake": "500"}
+		]
+	}`), &snapshot.Validators))
+	snapshot.ProtocolConfig.EpochLength = 1000
+	snapshot.Rewards = map[string]watcher.Rewards{
+		"kiln.pool.f863973.m0": {EpochHeight: 9, BlockTime: 1200 * time.Millisecond, APY: 10.5, NetAPY: 9.45},
+	}
+
+	store, err := history.Open(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, store.Save(history.Epoch{EpochHeight: 9, Validators: snapshot.Validators.CurrentValidators}))
+
+	api := New(map[string]Source{
+		"testnet": source{snapshot: snapshot, history: store},
+	})
+
+	t.Run("Status", func(t *testing.T) {
+		var status Status
+		assert.Equal(t, http.StatusOK, get(t, api, "/api/v1/status", &status))
+		assert.Equal(t, "testnet", status.ChainID)
+		assert.Equal(t, uint64(1250), status.LatestBlockHeight)
+	})
+
+	t.Run("Epoch", func(t *testing.T) {
+		var epoch Epoch
+		assert.Equal(t, http.StatusOK, get(t, api, "/api/v1/epoch?network=testnet", &epoch))
+		assert.Equal(t, int64(250), epoch.ElapsedBlocks)
+		assert.Equal(t, int64(750), epoch.RemainingBlocks)
+		assert.Equal(t, float64(25), epoch.Progress)
+	})
+
+	t.Run("Validators", func(t *testing.T) {
+		var list ValidatorList
+		assert.Equal(t, http.StatusOK, get(t, api, "/api/v1/validators", &list))
+		require.Len(t, list.Validators, 3)
+		assert.Equal(t, "kiln.pool.f863973.m0", list.Validators[0].AccountID)
+		assert.Equal(t, 1, list.Validators[0].Rank)
+		assert.True(t, list.Validators[0].Tracked)
+		assert.True(t, list.Validators[0].InNextEpoch)
+		assert.Equal(t, float64(90), list.Validators[0].UptimeBlocks)
+		require.NotNil(t, list.Validators[0].Rewards)
+		assert.Equal(t, 1.2, list.Validators[0].Rewards.BlockTime)
+		assert.Equal(t, 9.45, list.Validators[0].Rewards.NetAPY)
+		assert.Equal(t, "node1", list.Validators[1].AccountID)
+		assert.False(t, list.Validators[1].InNextEpoch)
+		assert.Equal(t, "node2", list.Validators[2].AccountID)
+		assert.Equal(t, 0, list.Validators[2].Rank)
+
+		assert.Equal(t, http.StatusOK, get(t, api, "/api/v1/validators?tracked=true", &list))
+		assert.Len(t, list.Validators, 1)
+
+		var validator Validator
+		assert.Equal(t, http.StatusOK, get(t, api, "/api/v1/validators/node1", &validator))
+		assert.Equal(t, 2, validator.Rank)
+		assert.Equal(t, "1000", validator.Stake.String())
+
+		assert.Equal(t, http.StatusNotFound, get(t, api, "/api/v1/validators/unknown", nil))
+	})
+
+	t.Run("Epochs", func(t *testing.T) {
+		var list EpochList
+		assert.Equal(t, http.StatusOK, get(t, api, "/api/v1/epochs", &list))
+		assert.Equal(t, []int64{9}, list.EpochHeights)
+
+		var record EpochRecord
+		assert.Equal(t, http.StatusOK, get(t, api, "/api/v1/epochs/9", &record))
+		require.Len(t, record.Validators, 2)
+		for _, v := range record.Validators {
+			assert.Equal(t, v.AccountID == "kiln.pool.f863973.m0", v.Tracked, v.AccountID)
+		}
+
+		assert.Equal(t, http.StatusNotFound, get(t, api, "/api/v1/epochs/8", nil))
+		assert.Equal(t, http.StatusBadRequest, get(t, api, "/api/v1/epochs/latest", nil))
+	})
+
+	t.Run("Networks", func(t *testing.T) {
+		api := New(map[string]Source{
+			"mainnet": source{},
+			"testnet": source{snapshot: snapshot},
+		})
+
+		var body map[string]string
+		assert.Equal(t, http.StatusBadRequest, get(t, api, "/api/v1/status", &body))
+		assert.Equal(t, "network query parameter is required (mainnet, testnet)", body["error"])
+		assert.Equal(t, http.StatusServiceUnavailable, get(t, api, "/api/v1/status?network=mainnet", nil))
+		assert.Equal(t, http.StatusNotFound, get(t, api, "/api/v1/status?network=betanet", nil))
+		assert.Equal(t, http.StatusOK, get(t, api, "/api/v1/status?network=testnet", nil))
+		assert.Equal(t, http.StatusNotFound, get(t, api, "/api/v1/epochs?network=testnet", nil))
+	})
+}
@@ -0,0 +1,243 @@
+package api
+
+import (
+	"sort"
+	"time"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/history"
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/kilnfi/near-validator-watcher/pkg/watcher"
+	"github.com/shopspring/decimal"
+)
+
+type Status struct {
+	Network               string    `json:"network"`
+	ChainID               string    `json:"chain_id"`
+	ProtocolVersion       int       `json:"protocol_version"`
+	LatestProtocolVersion int       `json:"latest_protocol_version"`
+	Version               string    `json:"version"`
+	Build                 string    `json:"build"`
+	LatestBlockHeight     uint64    `json:"latest_block_height"`
+	LatestBlockHash       string    `json:"latest_block_hash"`
+	LatestBlockTime       string    `json:"latest_block_time"`
+	Syncing               bool      `json:"syncing"`
+	ValidatorAccountID    string    `json:"validator_account_id,omitempty"`
+	ValidatorPublicKey    string    `json:"validator_public_key,omitempty"`
+	NodePublicKey         string    `json:"node_public_key,omitempty"`
+	CollectedAt           time.Time `json:"collected_at"`
+}
+
+func newStatus(s *watcher.Snapshot) Status {
+	return Status{
+		Network:               s.Network,
+		ChainID:               s.Status.ChainID,
+		ProtocolVersion:       s.Status.ProtocolVersion,
+		LatestProtocolVersion: s.Status.LatestProtocolVersion,
+		Version:               s.Status.Version.Version,
+		Build:                 s.Status.Version.Build,
+		LatestBlockHeight:     s.Status.SyncInfo.LatestBlockHeight,
+		LatestBlockHash:       s.Status.SyncInfo.LatestBlockHash,
+		LatestBlockTime:       s.Status.SyncInfo.LatestBlockTime,
+		Syncing:               s.Status.SyncInfo.Syncing,
+		ValidatorAccountID:    s.Status.ValidatorAccountID,
+		ValidatorPublicKey:    s.Status.ValidatorPublicKey,
+		NodePublicKey:         s.Status.NodePublicKey,
+		CollectedAt:           s.CollectedAt,
+	}
+}
+
+type Epoch struct {
+	Network                       string           `json:"network"`
+	EpochHeight                   int64            `json:"epoch_height"`
+	EpochStartHeight              int64            `json:"epoch_start_height"`
+	EpochLength                   int              `json:"epoch_length"`
+	LatestBlockHeight             uint64           `json:"latest_block_height"`
+	ElapsedBlocks                 int64            `json:"elapsed_blocks"`
+	RemainingBlocks               int64            `json:"remaining_blocks"`
+	Progress                      float64          `json:"progress"`
+	SeatPrice                     *decimal.Decimal `json:"seat_price,omitempty"`
+	BlockProducerKickoutThreshold int              `json:"block_producer_kickout_threshold"`
+	ChunkProducerKickoutThreshold int              `json:"chunk_producer_kickout_threshold"`
+	Validators                    int              `json:"validators"`
+	NextValidators                int              `json:"next_validators"`
+	CollectedAt                   time.Time        `json:"collected_at"`
+}
+
+func newEpoch(s *watcher.Snapshot) Epoch {
+	var (
+		elapsed, remaining = s.EpochProgress()
+		blocks, chunks     = s.KickoutThresholds()
+	)
+
+	epoch := Epoch{
+		Network:                       s.Network,
+		EpochHeight:                   s.Validators.EpochHeight,
+		EpochStartHeight:              s.Validators.EpochStartHeight,
+		EpochLength:                   s.ProtocolConfig.EpochLength,
+		LatestBlockHeight:             s.Status.SyncInfo.LatestBlockHeight,
+		ElapsedBlocks:                 elapsed,
+		RemainingBlocks:               remaining,
+		BlockProducerKickoutThreshold: blocks,
+		ChunkProducerKickoutThreshold: chunks,
+		Validators:                    len(s.Validators.CurrentValidators),
+		NextValidators:                len(s.Validators.NextValidators),
+		SeatPrice:                     s.SeatPrice,
+		CollectedAt:                   s.CollectedAt,
+	}
+	if s.ProtocolConfig.EpochLength > 0 {
+		epoch.Progress = 100 * float64(elapsed) / float64(s.ProtocolConfig.EpochLength)
+	}
+
+	return epoch
+}
+
+// Validator is a current, next or proposed validator. Stakes are in yoctoNEAR
+// and uptimes in percent, the epoch stats are only set for current validators.
+type Validator struct {
+	AccountID     string           `json:"account_id"`
+	PublicKey     string           `json:"public_key"`
+	Tracked       bool             `json:"tracked"`
+	Rank          int              `json:"rank,omitempty"`
+	Stake         *decimal.Decimal `json:"stake,omitempty"`
+	NextStake     *decimal.Decimal `json:"next_stake,omitempty"`
+	ProposalStake *decimal.Decimal `json:"proposal_stake,omitempty"`
+	InNextEpoch   bool             `json:"in_next_epoch,omitempty"`
+	IsSlashed     bool             `json:"is_slashed"`
+	Shards        []int            `json:"shards,omitempty"`
+
+	NumProducedBlocks       int64   `json:"num_produced_blocks"`
+	NumExpectedBlocks       int64   `json:"num_expected_blocks"`
+	NumProducedChunks       int64   `json:"num_produced_chunks"`
+	NumExpectedChunks       int64   `json:"num_expected_chunks"`
+	NumProducedEndorsements int64   `json:"num_produced_endorsements"`
+	NumExpectedEndorsements int64   `json:"num_expected_endorsements"`
+	UptimeBlocks            float64 `json:"uptime_blocks"`
+	UptimeChunks            float64 `json:"uptime_chunks"`
+	UptimeEndorsements      float64 `json:"uptime_endorsements"`
+
+	Rewards *Rewards `json:"rewards,omitempty"`
+}
+
+// Rewards is the estimated reward of a tracked staking pool over its last
+// full epoch, the APYs are in percent.
+type Rewards struct {
+	EpochHeight int64           `json:"epoch_height"`
+	Reward      decimal.Decimal `json:"reward"`
+	BlockTime   float64         `json:"block_time_seconds"`
+	APY         float64         `json:"apy"`
+	NetAPY      float64         `json:"net_apy"`
+}
+
+type ValidatorList struct {
+	Network     string      `json:"network"`
+	EpochHeight int64       `json:"epoch_height"`
+	Validators  []Validator `json:"validators"`
+}
+
+// newValidators returns the current validators ranked by stake, followed by
+// the next and proposed validators which aren't current ones.
+func newValidators(s *watcher.Snapshot) []Validator {
+	var (
+		validators = make([]Validator, 0, len(s.Validators.CurrentValidators))
+		index      = make(map[string]int)
+	)
+
+	current := make([]near.CurrentEpochValidatorInfo, len(s.Validators.CurrentValidators))
+	copy(current, s.Validators.CurrentValidators)
+	sort.SliceStable(current, func(i, j int) bool {
+		return current[i].Stake.GreaterThan(current[j].Stake)
+	})
+
+	for i, v := range current {
+		stake := v.Stake
+		index[v.AccountId] = len(validators)
+		validators = append(validators, Validator{
+			AccountID:               v.AccountId,
+			PublicKey:               v.PublicKey,
+			Tracked:                 s.IsTracked(v.AccountId),
+			Rank:                    i + 1,
+			Stake:                   &stake,
+			IsSlashed:               v.IsSlashed,
+			Shards:                  v.Shards,
+			NumProducedBlocks:       v.NumProducedBlocks,
+			NumExpectedBlocks:       v.NumExpectedBlocks,
+			NumProducedChunks:       v.NumProducedChunks,
+			NumExpectedChunks:       v.NumExpectedChunks,
+			NumProducedEndorsements: v.NumProducedEndorsements,
+			NumExpectedEndorsements: v.NumExpectedEndorsements,
+			UptimeBlocks:            v.BlocksUptime(),
+			UptimeChunks:            v.ChunksUptime(),
+			UptimeEndorsements:      v.EndorsementsUptime(),
+		})
+	}
+
+	lookup := func(v near.Validator) *Validator {
+		i, ok := index[v.AccountId]
+		if !ok {
+			index[v.AccountId] = len(validators)
+			validators = append(validators, Validator{
+				AccountID: v.AccountId,
+				PublicKey: v.PublicKey,
+				Tracked:   s.IsTracked(v.AccountId),
+			})
+			i = len(validators) - 1
+		}
+		return &validators[i]
+	}
+
+	for _, v := range s.Validators.NextValidators {
+		stake := v.Stake
+		validator := lookup(v.Validator)
+		validator.InNextEpoch = true
+		validator.NextStake = &stake
+	}
+	for _, v := range s.Validators.CurrentProposals {
+		stake := v.Stake
+		lookup(v.Validator).ProposalStake = &stake
+	}
+
+	for i := range validators {
+		if r, ok := s.Rewards[validators[i].AccountID]; ok {
+			validators[i].Rewards = &Rewards{
+				EpochHeight: r.EpochHeight,
+				Reward:      r.Reward,
+				BlockTime:   r.BlockTime.Seconds(),
+				APY:         r.APY,
+				NetAPY:      r.NetAPY,
+			}
+		}
+	}
+
+	return validators
+}
+
+type EpochList struct {
+	Network      string  `json:"network"`
+	EpochHeights []int64 `json:"epoch_heights"`
+}
+
+type EpochRecord struct {
+	Network          string      `json:"network"`
+	EpochHeight      int64       `json:"epoch_height"`
+	EpochStartHeight int64       `json:"epoch_start_height"`
+	LastBlockHeight  int64       `json:"last_block_height"`
+	RecordedAt       time.Time   `json:"recorded_at"`
+	Validators       []Validator `json:"validators"`
+}
+
+// newEpochRecord builds the record of a past epoch, validators are flagged as
+// tracked by the currently tracked accounts.
+func newEpochRecord(network string, epoch history.Epoch, trackedAccounts []string) EpochRecord {
+	return EpochRecord{
+		Network:          network,
+		EpochHeight:      epoch.EpochHeight,
+		EpochStartHeight: epoch.EpochStartHeight,
+		LastBlockHeight:  epoch.LastBlockHeight,
+		RecordedAt:       epoch.RecordedAt,
+		Validators: newValidators(&watcher.Snapshot{
+			Network:         network,
+			Validators:      near.ValidatorsResponse{CurrentValidators: epoch.Validators},
+			TrackedAccounts: trackedAccounts,
+		}),
+	}
+}
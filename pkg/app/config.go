@@ -0,0 +1,427 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/kilnfi/near-validator-watcher/pkg/watcher"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds everything that can be set through flags, plus per-validator
+// and per-network settings that can only be set through a configuration file.
+type Config struct {
+	CollectTimeout    time.Duration     `yaml:"collect-timeout" toml:"collect-timeout"`
+	HistoryDir        string            `yaml:"history-dir" toml:"history-dir"`
+	HTTPAddr          string            `yaml:"http-addr" toml:"http-addr"`
+	LogLevel          string            `yaml:"log-level" toml:"log-level"`
+	Namespace         string            `yaml:"namespace" toml:"namespace"`
+	Network           string            `yaml:"network" toml:"network"`
+	Networks          []NetworkConfig   `yaml:"networks" toml:"networks"`
+	NoColor           bool              `yaml:"no-color" toml:"no-color"`
+	Nodes             []string          `yaml:"nodes" toml:"nodes"`
+	NodeRecoveryDelay time.Duration     `yaml:"node-recovery-delay" toml:"node-recovery-delay"`
+	ReferenceNodes    []string          `yaml:"reference-nodes" toml:"reference-nodes"`
+	RefreshRate       time.Duration     `yaml:"refresh-rate" toml:"refresh-rate"`
+	ReloadToken       string            `yaml:"reload-token" toml:"reload-token"`
+	TUI               bool              `yaml:"tui" toml:"tui"`
+	TUITopValidators  int               `yaml:"tui-top-validators" toml:"tui-top-validators"`
+	Alert             AlertConfig       `yaml:"alert" toml:"alert"`
+	Health            HealthConfig      `yaml:"health" toml:"health"`
+	Report            ReportConfig      `yaml:"report" toml:"report"`
+	Validators        []ValidatorConfig `yaml:"validators" toml:"validators"`
+}
+
+type AlertConfig struct {
+	Webhook         string        `yaml:"webhook" toml:"webhook"`
+	RepeatInterval  time.Duration `yaml:"repeat-interval" toml:"repeat-interval"`
+	UptimeThreshold float64       `yaml:"uptime-threshold" toml:"uptime-threshold"`
+}
+
+// HealthConfig holds the thresholds of the readiness and liveness checks, 0
+// disables a check.
+type HealthConfig struct {
+	MaxBlockAge      time.Duration `yaml:"max-block-age" toml:"max-block-age"`
+	MaxCollectionAge time.Duration `yaml:"max-collection-age" toml:"max-collection-age"`
+	MaxCycleAge      time.Duration `yaml:"max-cycle-age" toml:"max-cycle-age"`
+	MaxFailures      int           `yaml:"max-failures" toml:"max-failures"`
+}
+
+// ReportConfig enables the epoch end reports of the tracked validators when
+// Dir is set.
+type ReportConfig struct {
+	Dir     string `yaml:"dir" toml:"dir"`
+	Webhook string `yaml:"webhook" toml:"webhook"`
+}
+
+// NetworkConfig holds the settings of a watched network. Its refresh rate and
+// recovery delay default to the global ones.
+type NetworkConfig struct {
+	Name              string            `yaml:"name" toml:"name"`
+	Nodes             []string          `yaml:"nodes" toml:"nodes"`
+	NodeRecoveryDelay time.Duration     `yaml:"node-recovery-delay" toml:"node-recovery-delay"`
+	ReferenceNodes    []string          `yaml:"reference-nodes" toml:"reference-nodes"`
+	RefreshRate       time.Duration     `yaml:"refresh-rate" toml:"refresh-rate"`
+	Validators        []ValidatorConfig `yaml:"validators" toml:"validators"`
+}
+
+type ValidatorConfig struct {
+	AccountID string `yaml:"account-id" toml:"account-id"`
+	// Name is displayed instead of the account id
+	Name string `yaml:"name" toml:"name"`
+	// UptimeThreshold overrides the global alert uptime threshold
+	UptimeThreshold float64 `yaml:"uptime-threshold" toml:"uptime-threshold"`
+	// Labels are added to the alerts of the validator
+	Labels map[string]string `yaml:"labels" toml:"labels"`
+}
+
+// networkFlags are the flags defining the single watched network, they can't be
+// combined with the networks of the configuration file.
+var networkFlags = []string{"network", "node", "reference-node", "validator"}
+
+// LoadConfig builds the configuration from the flags default values, then the
+// configuration file if any, then the flags explicitly set.
+func LoadConfig(cCtx *cli.Context) (*Config, error) {
+	config := &Config{}
+	applyFlags(cCtx, config, false)
+
+	if path := cCtx.String("config"); path != "" {
+		if err := config.readFile(path); err != nil {
+			return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+		}
+		applyFlags(cCtx, config, true)
+
+		if len(config.Networks) > 0 {
+			set := make([]string, 0, len(networkFlags))
+			for _, name := range networkFlags {
+				if cCtx.IsSet(name) {
+					set = append(set, "--"+name)
+				}
+			}
+			if len(set) > 0 {
+				return nil, fmt.Errorf("%s can't be used when networks are configured, set them per network in %s", strings.Join(set, ", "), path)
+			}
+		}
+	}
+
+	// Validators given as flags are added to the ones of the configuration file
+	for _, accountID := range cCtx.StringSlice("validator") {
+		if config.validator(accountID) == nil {
+			config.Validators = append(config.Validators, ValidatorConfig{AccountID: accountID})
+		}
+	}
+
+	for i := range config.Networks {
+		if config.Networks[i].RefreshRate == 0 {
+			config.Networks[i].RefreshRate = config.RefreshRate
+		}
+		if config.Networks[i].NodeRecoveryDelay == 0 {
+			config.Networks[i].NodeRecoveryDelay = config.NodeRecoveryDelay
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	// Without networks, a single network is defined by the top level settings
+	if len(config.Networks) == 0 {
+		config.Networks = []NetworkConfig{{
+			Name:              config.Network,
+			Nodes:             config.Nodes,
+			NodeRecoveryDelay: config.NodeRecoveryDelay,
+			RefreshRate:       config.RefreshRate,
+			ReferenceNodes:    config.ReferenceNodes,
+			Validators:        config.Validators,
+		}}
+	}
+
+	return config, nil
+}
+
+// applyFlags copies the flags values to the config, only the ones explicitly
+// set by the user when onlySet is true.
+func applyFlags(cCtx *cli.Context, config *Config, onlySet bool) {
+	isSet := func(name string) bool {
+		return !onlySet || cCtx.IsSet(name)
+	}
+
+	if isSet("alert-repeat-interval") {
+		config.Alert.RepeatInterval = cCtx.Duration("alert-repeat-interval")
+	}
+	if isSet("alert-uptime-threshold") {
+		config.Alert.UptimeThreshold = cCtx.Float64("alert-uptime-threshold")
+	}
+	if isSet("alert-webhook") {
+		config.Alert.Webhook = cCtx.String("alert-webhook")
+	}
+	if isSet("collect-timeout") {
+		config.CollectTimeout = cCtx.Duration("collect-timeout")
+	}
+	if isSet("health-max-block-age") {
+		config.Health.MaxBlockAge = cCtx.Duration("health-max-block-age")
+	}
+	if isSet("health-max-collection-age") {
+		config.Health.MaxCollectionAge = cCtx.Duration("health-max-collection-age")
+	}
+	if isSet("health-max-cycle-age") {
+		config.Health.MaxCycleAge = cCtx.Duration("health-max-cycle-age")
+	}
+	if isSet("health-max-failures") {
+		config.Health.MaxFailures = cCtx.Int("health-max-failures")
+	}
+	if isSet("history-dir") {
+		config.HistoryDir = cCtx.String("history-dir")
+	}
+	if isSet("http-addr") {
+		config.HTTPAddr = cCtx.String("http-addr")
+	}
+	if isSet("log-level") {
+		config.LogLevel = cCtx.String("log-level")
+	}
+	if isSet("namespace") {
+		config.Namespace = cCtx.String("namespace")
+	}
+	if isSet("network") {
+		config.Network = cCtx.String("network")
+	}
+	if isSet("no-color") {
+		config.NoColor = cCtx.Bool("no-color")
+	}
+	if isSet("node") {
+		config.Nodes = cCtx.StringSlice("node")
+	}
+	if isSet("node-recovery-delay") {
+		config.NodeRecoveryDelay = cCtx.Duration("node-recovery-delay")
+	}
+	if isSet("reference-node") {
+		config.ReferenceNodes = cCtx.StringSlice("reference-node")
+	}
+	if isSet("refresh-rate") {
+		config.RefreshRate = cCtx.Duration("refresh-rate")
+	}
+	if isSet("report-dir") {
+		config.Report.Dir = cCtx.String("report-dir")
+	}
+	if isSet("report-webhook") {
+		config.Report.Webhook = cCtx.String("report-webhook")
+	}
+	if isSet("reload-token") {
+		config.ReloadToken = cCtx.String("reload-token")
+	}
+	if isSet("tui") {
+		config.TUI = cCtx.Bool("tui")
+	}
+	if isSet("tui-top-validators") {
+		config.TUITopValidators = cCtx.Int("tui-top-validators")
+	}
+}
+
+func (c *Config) readFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+	case ".toml":
+		meta, err := toml.Decode(string(content), c)
+		if err != nil {
+			return err
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("unknown field %q", undecoded[0].String())
+		}
+	default:
+		return fmt.Errorf("unsupported file extension %q (expected .yaml, .yml or .toml)", ext)
+	}
+
+	return nil
+}
+
+// Tracking returns the settings of the tracked validators used by the watcher
+// of the network.
+func (n *NetworkConfig) Tracking(alertUptimeThreshold float64) watcher.Tracking {
+	tracking := watcher.Tracking{
+		TrackedAccounts:      make([]string, 0, len(n.Validators)),
+		Validators:           make(map[string]watcher.ValidatorConfig, len(n.Validators)),
+		AlertUptimeThreshold: alertUptimeThreshold,
+	}
+	for _, v := range n.Validators {
+		tracking.TrackedAccounts = append(tracking.TrackedAccounts, v.AccountID)
+		tracking.Validators[v.AccountID] = watcher.ValidatorConfig{
+			Name:            v.Name,
+			UptimeThreshold: v.UptimeThreshold,
+			Labels:          v.Labels,
+		}
+	}
+	return tracking
+}
+
+func (c *Config) validator(accountID string) *ValidatorConfig {
+	for i := range c.Validators {
+		if c.Validators[i].AccountID == accountID {
+			return &c.Validators[i]
+		}
+	}
+	return nil
+}
+
+// Validate checks the configuration and returns every error found.
+func (c *Config) Validate() error {
+	errs := make([]error, 0)
+
+	switch c.LogLevel {
+	case "debug", "info", "warn", "error":
+	default:
+		errs = append(errs, fmt.Errorf("log-level: unknown level %q (expected debug, info, warn or error)", c.LogLevel))
+	}
+	if c.Namespace == "" {
+		errs = append(errs, fmt.Errorf("namespace: must not be empty"))
+	}
+
+	if c.CollectTimeout < 0 {
+		errs = append(errs, fmt.Errorf("collect-timeout: must not be negative"))
+	}
+
+	if c.Alert.Webhook != "" {
+		if err := validateURL(c.Alert.Webhook); err != nil {
+			errs = append(errs, fmt.Errorf("alert.webhook: %w", err))
+		}
+	}
+	if c.Alert.RepeatInterval < 0 {
+		errs = append(errs, fmt.Errorf("alert.repeat-interval: must not be negative"))
+	}
+	if c.Alert.UptimeThreshold < 0 || c.Alert.UptimeThreshold > 100 {
+		errs = append(errs, fmt.Errorf("alert.uptime-threshold: must be between 0 and 100"))
+	}
+
+	if c.Health.MaxBlockAge < 0 {
+		errs = append(errs, fmt.Errorf("health.max-block-age: must not be negative"))
+	}
+	if c.Health.MaxCollectionAge < 0 {
+		errs = append(errs, fmt.Errorf("health.max-collection-age: must not be negative"))
+	}
+	if c.Health.MaxCycleAge < 0 {
+		errs = append(errs, fmt.Errorf("health.max-cycle-age: must not be negative"))
+	}
+	if c.Health.MaxFailures < 0 {
+		errs = append(errs, fmt.Errorf("health.max-failures: must not be negative"))
+	}
+
+	if c.TUITopValidators < 0 {
+		errs = append(errs, fmt.Errorf("tui-top-validators: must not be negative"))
+	}
+
+	if c.Report.Webhook != "" {
+		if err := validateURL(c.Report.Webhook); err != nil {
+			errs = append(errs, fmt.Errorf("report.webhook: %w", err))
+		}
+		if c.Report.Dir == "" {
+			errs = append(errs, fmt.Errorf("report.webhook: requires report.dir to be set"))
+		}
+	}
+
+	if len(c.Networks) == 0 {
+		if c.Network == "" {
+			errs = append(errs, fmt.Errorf("network: must not be empty"))
+		}
+		errs = append(errs, validateNetwork("", NetworkConfig{
+			Nodes:             c.Nodes,
+			NodeRecoveryDelay: c.NodeRecoveryDelay,
+			RefreshRate:       c.RefreshRate,
+			ReferenceNodes:    c.ReferenceNodes,
+			Validators:        c.Validators,
+		})...)
+		return errors.Join(errs...)
+	}
+
+	if len(c.Validators) > 0 {
+		errs = append(errs, fmt.Errorf("validators: must be set per network when networks are configured"))
+	}
+	seen := make(map[string]bool)
+	for i, network := range c.Networks {
+		prefix := fmt.Sprintf("networks[%d].", i)
+		if network.Name == "" {
+			errs = append(errs, fmt.Errorf("%sname: must not be empty", prefix))
+		} else if seen[network.Name] {
+			errs = append(errs, fmt.Errorf("%sname: duplicated network %q", prefix, network.Name))
+		}
+		seen[network.Name] = true
+		errs = append(errs, validateNetwork(prefix, network)...)
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateNetwork checks the settings of a network, errors are prefixed by the
+// path of the network in the configuration.
+func validateNetwork(prefix string, network NetworkConfig) []error {
+	errs := make([]error, 0)
+
+	if len(network.Nodes) == 0 {
+		errs = append(errs, fmt.Errorf("%snodes: at least one rpc endpoint is required", prefix))
+	}
+	for i, node := range network.Nodes {
+		if err := validateURL(node); err != nil {
+			errs = append(errs, fmt.Errorf("%snodes[%d]: %w", prefix, i, err))
+		}
+	}
+	for i, node := range network.ReferenceNodes {
+		if err := validateURL(node); err != nil {
+			errs = append(errs, fmt.Errorf("%sreference-nodes[%d]: %w", prefix, i, err))
+		}
+	}
+	if network.RefreshRate <= 0 {
+		errs = append(errs, fmt.Errorf("%srefresh-rate: must be positive", prefix))
+	}
+	if network.NodeRecoveryDelay < 0 {
+		errs = append(errs, fmt.Errorf("%snode-recovery-delay: must not be negative", prefix))
+	}
+
+	seen := make(map[string]bool)
+	for i, v := range network.Validators {
+		if v.AccountID == "" {
+			errs = append(errs, fmt.Errorf("%svalidators[%d]: account-id is required", prefix, i))
+			continue
+		}
+		if seen[v.AccountID] {
+			errs = append(errs, fmt.Errorf("%svalidators[%d]: duplicated account-id %q", prefix, i, v.AccountID))
+		}
+		seen[v.AccountID] = true
+		if v.UptimeThreshold < 0 || v.UptimeThreshold > 100 {
+			errs = append(errs, fmt.Errorf("%svalidators[%d]: uptime-threshold must be between 0 and 100", prefix, i))
+		}
+	}
+
+	return errs
+}
+
+func validateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid url %q: scheme must be http or https", rawURL)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid url %q: missing host", rawURL)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func loadConfig(t *testing.T, args ...string) (*Config, error) {
+	var (
+		config *Config
+		err    error
+	)
+
+	app := &cli.App{
+		Flags: Flags,
+		Action: func(cCtx *cli.Context) error {
+			config, err = LoadConfig(cCtx)
+			return nil
+		},
+	}
+	require.NoError(t, app.RunContext(context.Background(), append([]string{"near-validator-watcher"}, args...)))
+
+	return config, err
+}
+
+func writeConfigFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		config, err := loadConfig(t, "--validator", "kiln.poolv1.near")
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"https://rpc.mainnet.near.org"}, config.Nodes)
+		assert.Equal(t, ":8080", config.HTTPAddr)
+		assert.Equal(t, float64(90), config.Alert.UptimeThreshold)
+		assert.Equal(t, []ValidatorConfig{{AccountID: "kiln.poolv1.near"}}, config.Validators)
+
+		require.Len(t, config.Networks, 1)
+		assert.Equal(t, "mainnet", config.Networks[0].Name)
+		assert.Equal(t, config.Nodes, config.Networks[0].Nodes)
+		assert.Equal(t, config.Validators, config.Networks[0].Validators)
+	})
+
+	t.Run("YAML", func(t *testing.T) {
+		path := writeConfigFile(t, "config.yaml", `
+nodes:
+  - https://rpc.testnet.near.org
+refresh-rate: 30s
+alert:
+  webhook: https://hooks.example.com/near
+  uptime-threshold: 95
+validators:
+  - account-id: kiln.pool.f863973.m0
+    name: Kiln
+    uptime-threshold: 98
+    labels:
+      team: infra
+`)
+		config, err := loadConfig(t, "--config", path, "--validator", "other.pool.f863973.m0")
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"https://rpc.testnet.near.org"}, config.Nodes)
+		assert.Equal(t, 30*time.Second, config.RefreshRate)
+		assert.Equal(t, "https://hooks.example.com/near", config.Alert.Webhook)
+		assert.Equal(t, float64(95), config.Alert.UptimeThreshold)
+		assert.Equal(t, []ValidatorConfig{
+			{AccountID: "kiln.pool.f863973.m0", Name: "Kiln", UptimeThreshold: 98, Labels: map[string]string{"team": "infra"}},
+			{AccountID: "other.pool.f863973.m0"},
+		}, config.Validators)
+	})
+
+	t.Run("TOML", func(t *testing.T) {
+		path := writeConfigFile(t, "config.toml", `
+nodes = ["https://rpc.testnet.near.org"]
+refresh-rate = "30s"
+
+[[validators]]
+account-id = "kiln.pool.f863973.m0"
+name = "Kiln"
+`)
+		config, err := loadConfig(t, "--config", path)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"https://rpc.testnet.near.org"}, config.Nodes)
+		assert.Equal(t, 30*time.Second, config.RefreshRate)
+		assert.Equal(t, []ValidatorConfig{{AccountID: "kiln.pool.f863973.m0", Name: "Kiln"}}, config.Validators)
+	})
+
+	t.Run("Networks", func(t *testing.T) {
+		path := writeConfigFile(t, "config.yaml", `
+refresh-rate: 20s
+networks:
+  - name: mainnet
+    nodes: [https://rpc.mainnet.near.org]
+    validators:
+      - account-id: kiln.poolv1.near
+  - name: testnet
+    nodes: [https://rpc.testnet.near.org]
+    reference-nodes: [https://archival-rpc.testnet.near.org]
+    refresh-rate: 1m
+    validators:
+      - account-id: kiln.pool.f863973.m0
+`)
+		config, err := loadConfig(t, "--config", path)
+		require.NoError(t, err)
+
+		require.Len(t, config.Networks, 2)
+		assert.Equal(t, "mainnet", config.Networks[0].Name)
+		assert.Equal(t, 20*time.Second, config.Networks[0].RefreshRate)
+		assert.Equal(t, 30*time.Second, config.Networks[0].NodeRecoveryDelay)
+		assert.Equal(t, "testnet", config.Networks[1].Name)
+		assert.Equal(t, time.Minute, config.Networks[1].RefreshRate)
+		assert.Equal(t, []string{"https://archival-rpc.testnet.near.org"}, config.Networks[1].ReferenceNodes)
+		assert.Equal(t, []string{"kiln.pool.f863973.m0"}, config.Networks[1].Tracking(90).TrackedAccounts)
+
+		_, err = loadConfig(t, "--config", path, "--node", "https://rpc.mainnet.near.org", "--network", "mainnet")
+		assert.ErrorContains(t, err, "--network, --node can't be used when networks are configured")
+
+		_, err = loadConfig(t, "--config", path, "--validator", "kiln.poolv1.near")
+		assert.ErrorContains(t, err, "--validator can't be used when networks are configured")
+	})
+
+	t.Run("Flags take precedence", func(t *testing.T) {
+		path := writeConfigFile(t, "config.yaml", `
+refresh-rate: 30s
+http-addr: ":9090"
+`)
+		config, err := loadConfig(t, "--config", path, "--refresh-rate", "1m")
+		require.NoError(t, err)
+
+		assert.Equal(t, time.Minute, config.RefreshRate)
+		assert.Equal(t, ":9090", config.HTTPAddr)
+	})
+
+	t.Run("Unknown field", func(t *testing.T) {
+		path := writeConfigFile(t, "config.yaml", `refresh-rates: 30s`)
+		_, err := loadConfig(t, "--config", path)
+		assert.ErrorContains(t, err, "refresh-rates")
+
+		path = writeConfigFile(t, "config.toml", `refresh-rates = "30s"`)
+		_, err = loadConfig(t, "--config", path)
+		assert.ErrorContains(t, err, `unknown field "refresh-rates"`)
+	})
+
+	t.Run("Invalid values", func(t *testing.T) {
+		path := writeConfigFile(t, "config.yaml", `
+nodes:
+  - rpc.mainnet.near.org
+log-level: verbose
+validators:
+  - name: Kiln
+  - account-id: kiln.poolv1.near
+    uptime-threshold: 120
+report:
+  webhook: https://hooks.example.com/reports
+`)
+		_, err := loadConfig(t, "--config", path)
+		require.Error(t, err)
+
+		assert.ErrorContains(t, err, `nodes[0]: invalid url "rpc.mainnet.near.org"`)
+		assert.ErrorContains(t, err, `log-level: unknown level "verbose"`)
+		assert.ErrorContains(t, err, "validators[0]: account-id is required")
+		assert.ErrorContains(t, err, "validators[1]: uptime-threshold must be between 0 and 100")
+		assert.ErrorContains(t, err, "report.webhook: requires report.dir to be set")
+	})
+}
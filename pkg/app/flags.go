@@ -7,6 +7,51 @@ import (
 )
 
 var Flags = []cli.Flag{
+	&cli.DurationFlag{
+		Name:  "alert-repeat-interval",
+		Usage: "how often to send firing alerts again (0 to only send them once)",
+	},
+	&cli.Float64Flag{
+		Name:  "alert-uptime-threshold",
+		Usage: "uptime (in percent) below which an alert is raised for tracked validators",
+		Value: 90,
+	},
+	&cli.StringFlag{
+		Name:  "alert-webhook",
+		Usage: "webhook url to send alerts to as JSON",
+	},
+	&cli.DurationFlag{
+		Name:  "collect-timeout",
+		Usage: "timeout of each data collector, retries included (defaults to the refresh rate)",
+	},
+	&cli.StringFlag{
+		Name:  "config",
+		Usage: "configuration file (.yaml, .yml or .toml), flags take precedence over its values",
+	},
+	&cli.DurationFlag{
+		Name:  "health-max-block-age",
+		Usage: "age of the latest block of the node above which the watcher isn't ready (0 to disable)",
+		Value: 2 * time.Minute,
+	},
+	&cli.DurationFlag{
+		Name:  "health-max-collection-age",
+		Usage: "time without successful collection above which the watcher isn't ready (0 to disable)",
+		Value: 2 * time.Minute,
+	},
+	&cli.DurationFlag{
+		Name:  "health-max-cycle-age",
+		Usage: "time without completed collection cycle above which the watcher isn't live (0 to disable)",
+		Value: 5 * time.Minute,
+	},
+	&cli.IntFlag{
+		Name:  "health-max-failures",
+		Usage: "number of consecutive failed collections from which the watcher isn't ready (0 to disable)",
+		Value: 3,
+	},
+	&cli.StringFlag{
+		Name:  "history-dir",
+		Usage: "directory where the final stats of each epoch are recorded (disabled when empty)",
+	},
 	&cli.StringFlag{
 		Name:  "http-addr",
 		Usage: "http server address",
@@ -22,20 +67,56 @@ var Flags = []cli.Flag{
 		Usage: "prefix for Prometheus metrics",
 		Value: "near_validator_watcher",
 	},
+	&cli.StringFlag{
+		Name:  "network",
+		Usage: "name of the network watched with --node and --validator, added as network label to metrics",
+		Value: "mainnet",
+	},
 	&cli.BoolFlag{
 		Name:  "no-color",
 		Usage: "disable colored output",
 	},
-	&cli.StringFlag{
+	&cli.StringSliceFlag{
 		Name:  "node",
-		Usage: "rpc node endpoint to connect to",
-		Value: "https://rpc.mainnet.near.org",
+		Usage: "rpc node endpoint to connect to, repeat to add fallback endpoints by order of preference",
+		Value: cli.NewStringSlice("https://rpc.mainnet.near.org"),
+	},
+	&cli.DurationFlag{
+		Name:  "node-recovery-delay",
+		Usage: "how long a failing rpc endpoint is put aside before being tried again",
+		Value: 30 * time.Second,
+	},
+	&cli.StringSliceFlag{
+		Name:  "reference-node",
+		Usage: "rpc endpoint the watched node is compared to in order to detect lags and forks, repeat to add more",
 	},
 	&cli.DurationFlag{
 		Name:  "refresh-rate",
 		Usage: "how often to call the rpc endpoint",
 		Value: 10 * time.Second,
 	},
+	&cli.StringFlag{
+		Name:    "reload-token",
+		Usage:   "bearer token required to reload the configuration via POST /-/reload (endpoint disabled when empty)",
+		EnvVars: []string{"RELOAD_TOKEN"},
+	},
+	&cli.StringFlag{
+		Name:  "report-dir",
+		Usage: "directory where a report of each tracked validator is written at the end of each epoch (disabled when empty)",
+	},
+	&cli.StringFlag{
+		Name:  "report-webhook",
+		Usage: "webhook url to post epoch reports to as JSON (requires --report-dir)",
+	},
+	&cli.BoolFlag{
+		Name:  "tui",
+		Usage: "display a full screen dashboard instead of the logs and status lines",
+	},
+	&cli.IntFlag{
+		Name:  "tui-top-validators",
+		Usage: "number of validators displayed by rank on the dashboard, besides the tracked ones",
+		Value: 10,
+	},
 	&cli.StringSliceFlag{
 		Name:  "validator",
 		Usage: "validator pool id to track",
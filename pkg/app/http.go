@@ -2,16 +2,20 @@ package app
 
 import (
 	"context"
+	"crypto/subtle"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
+	"github.com/kilnfi/near-validator-watcher/pkg/watcher"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
 )
 
-type Probe func() bool
-
-var upProbe Probe = func() bool { return true }
+// HealthProbe returns the result of the health checks of a network.
+type HealthProbe func() watcher.Health
 
 type HTTPServer struct {
 	*http.Server
@@ -19,18 +23,21 @@ type HTTPServer struct {
 
 type HTTPMuxOption func(*http.ServeMux)
 
-func withProbe(path string, probe Probe) HTTPMuxOption {
+// WithReadyProbes serves the readiness checks of each network on /ready as
+// JSON, it responds OK when every network is ready, or the one given as
+// network query parameter.
+func WithReadyProbes(probes map[string]HealthProbe) HTTPMuxOption {
 	return func(mux *http.ServeMux) {
-		mux.HandleFunc(path, probeHandler(probe))
+		mux.HandleFunc("/ready", healthProbesHandler("ready", probes))
 	}
 }
 
-func WithReadyProbe(probe Probe) HTTPMuxOption {
-	return withProbe("/ready", probe)
-}
-
-func WithLiveProbe(probe Probe) HTTPMuxOption {
-	return withProbe("/live", probe)
+// WithLiveProbes serves the liveness checks of each network on /live, like
+// WithReadyProbes.
+func WithLiveProbes(probes map[string]HealthProbe) HTTPMuxOption {
+	return func(mux *http.ServeMux) {
+		mux.HandleFunc("/live", healthProbesHandler("live", probes))
+	}
 }
 
 func WithMetrics(registry *prometheus.Registry) HTTPMuxOption {
@@ -39,6 +46,23 @@ func WithMetrics(registry *prometheus.Registry) HTTPMuxOption {
 	}
 }
 
+// WithHandler serves the handler on the given pattern.
+func WithHandler(pattern string, handler http.Handler) HTTPMuxOption {
+	return func(mux *http.ServeMux) {
+		mux.Handle(pattern, handler)
+	}
+}
+
+// WithReload exposes POST /-/reload to reload the configuration, requests must
+// be authenticated with the given bearer token. Nothing is exposed without token.
+func WithReload(token string, reload func() error) HTTPMuxOption {
+	return func(mux *http.ServeMux) {
+		if token != "" {
+			mux.HandleFunc("/-/reload", reloadHandler(token, reload))
+		}
+	}
+}
+
 func NewHTTPServer(addr string, options ...HTTPMuxOption) *HTTPServer {
 	mux := http.NewServeMux()
 	server := &HTTPServer{
@@ -73,12 +97,63 @@ func (s *HTTPServer) listenAndServe() error {
 	return nil
 }
 
-func probeHandler(probe Probe) http.HandlerFunc {
+func reloadHandler(token string, reload func() error) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if probe() {
-			w.WriteHeader(http.StatusNoContent)
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		logrus.Info("reload requested via http, reloading configuration")
+		if err := reload(); err != nil {
+			logrus.WithError(err).Error("failed to reload configuration")
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// healthProbesHandler responds with the result of every check of the probes
+// under the networks key, and whether they all pass under the given key.
+func healthProbesHandler(key string, probes map[string]HealthProbe) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		selected := probes
+		if network := r.URL.Query().Get("network"); network != "" {
+			probe, ok := probes[network]
+			if !ok {
+				http.Error(w, fmt.Sprintf("unknown network %q", network), http.StatusNotFound)
+				return
+			}
+			selected = map[string]HealthProbe{network: probe}
+		}
+
+		var (
+			networks = make(map[string]watcher.Health, len(selected))
+			healthy  = true
+		)
+		for network, probe := range selected {
+			networks[network] = probe()
+			healthy = healthy && networks[network].Healthy
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if healthy {
+			w.WriteHeader(http.StatusOK)
 		} else {
 			w.WriteHeader(http.StatusServiceUnavailable)
 		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			key:        healthy,
+			"networks": networks,
+		})
 	}
 }
@@ -0,0 +1,130 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/watcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthProbesHandler(t *testing.T) {
+	var (
+		ready = watcher.Health{Healthy: true, Checks: []watcher.Check{
+			{Name: "synced", OK: true},
+			{Name: "block_age", OK: true},
+		}}
+		stale = watcher.Health{Checks: []watcher.Check{
+			{Name: "synced", OK: true},
+			{Name: "block_age", Message: "latest block is 5m0s old, more than 1m0s"},
+		}}
+		neverSynced = watcher.Health{Checks: []watcher.Check{
+			{Name: "collection", Message: "no successful collection yet"},
+		}}
+	)
+
+	handler := healthProbesHandler("ready", map[string]HealthProbe{
+		"mainnet":  func() watcher.Health { return ready },
+		"testnet":  func() watcher.Health { return stale },
+		"localnet": func() watcher.Health { return neverSynced },
+	})
+
+	probe := func(t *testing.T, target string) (int, map[string]watcher.Health, bool) {
+		res := httptest.NewRecorder()
+		handler(res, httptest.NewRequest(http.MethodGet, target, nil))
+		if res.Code == http.StatusNotFound {
+			return res.Code, nil, false
+		}
+
+		var body struct {
+			Ready    bool                      `json:"ready"`
+			Networks map[string]watcher.Health `json:"networks"`
+		}
+		assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		return res.Code, body.Networks, body.Ready
+	}
+
+	t.Run("Ready", func(t *testing.T) {
+		code, networks, ok := probe(t, "/ready?network=mainnet")
+		assert.Equal(t, http.StatusOK, code)
+		assert.True(t, ok)
+		assert.Equal(t, map[string]watcher.Health{"mainnet": ready}, networks)
+	})
+
+	t.Run("Stale", func(t *testing.T) {
+		code, networks, ok := probe(t, "/ready?network=testnet")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.False(t, ok)
+		assert.Equal(t, map[string]watcher.Health{"testnet": stale}, networks)
+	})
+
+	t.Run("Never synced", func(t *testing.T) {
+		code, networks, ok := probe(t, "/ready?network=localnet")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.False(t, ok)
+		assert.Equal(t, map[string]watcher.Health{"localnet": neverSynced}, networks)
+	})
+
+	t.Run("Every network", func(t *testing.T) {
+		code, networks, ok := probe(t, "/ready")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.False(t, ok)
+		assert.Len(t, networks, 3)
+	})
+
+	t.Run("Unknown network", func(t *testing.T) {
+		code, _, _ := probe(t, "/ready?network=unknown")
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+}
+
+func TestReloadHandler(t *testing.T) {
+	var (
+		reloads   int
+		reloadErr error
+	)
+	handler := reloadHandler("secret", func() error {
+		reloads++
+		return reloadErr
+	})
+
+	reload := func(method string, authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/-/reload", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		res := httptest.NewRecorder()
+		handler(res, req)
+		return res
+	}
+
+	t.Run("Method", func(t *testing.T) {
+		res := reload(http.MethodGet, "Bearer secret")
+		assert.Equal(t, http.StatusMethodNotAllowed, res.Code)
+		assert.Equal(t, http.MethodPost, res.Header().Get("Allow"))
+		assert.Equal(t, 0, reloads)
+	})
+
+	t.Run("Token", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, reload(http.MethodPost, "").Code)
+		assert.Equal(t, http.StatusUnauthorized, reload(http.MethodPost, "Bearer wrong").Code)
+		assert.Equal(t, http.StatusUnauthorized, reload(http.MethodPost, "secret").Code)
+		assert.Equal(t, 0, reloads)
+
+		assert.Equal(t, http.StatusNoContent, reload(http.MethodPost, "Bearer secret").Code)
+		assert.Equal(t, 1, reloads)
+	})
+
+	t.Run("Reload error", func(t *testing.T) {
+		reloadErr = errors.New("invalid config: log-level: unknown level")
+		res := reload(http.MethodPost, "Bearer secret")
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Contains(t, res.Body.String(), "invalid config")
+		assert.Equal(t, 2, reloads)
+	})
+}
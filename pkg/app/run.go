@@ -2,14 +2,22 @@ package app
 
 import (
 	"context"
+	"io"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/kilnfi/near-validator-watcher/pkg/alert"
+	"github.com/kilnfi/near-validator-watcher/pkg/api"
+	"github.com/kilnfi/near-validator-watcher/pkg/dashboard"
+	"github.com/kilnfi/near-validator-watcher/pkg/history"
 	"github.com/kilnfi/near-validator-watcher/pkg/metrics"
 	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/kilnfi/near-validator-watcher/pkg/report"
 	"github.com/kilnfi/near-validator-watcher/pkg/watcher"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
@@ -17,32 +25,72 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
+// dashboardEvents is the number of recent events displayed on the dashboard.
+const dashboardEvents = 10
+
 func RunFunc(cCtx *cli.Context) error {
-	var (
-		ctx = cCtx.Context
-
-		// Config flags
-		httpAddr    = cCtx.String("http-addr")
-		logLevel    = cCtx.String("log-level")
-		namespace   = cCtx.String("namespace")
-		noColor     = cCtx.Bool("no-color")
-		node        = cCtx.String("node")
-		refreshRate = cCtx.Duration("refresh-rate")
-		validators  = cCtx.StringSlice("validator")
-	)
+	ctx := cCtx.Context
+
+	config, err := LoadConfig(cCtx)
+	if err != nil {
+		return err
+	}
 
 	//
 	// Setup
 	//
 	// Logger setup
 	logrus.SetOutput(os.Stdout)
-	logrus.SetLevel(logLevelFromString(logLevel))
+	logrus.SetLevel(logLevelFromString(config.LogLevel))
 	logrus.SetFormatter(&logrus.TextFormatter{
-		DisableColors: noColor,
+		DisableColors: config.NoColor,
 	})
 
 	// Disable colored output if requested
-	color.NoColor = noColor
+	color.NoColor = config.NoColor
+
+	// The dashboard takes over the terminal, logs are displayed as its events
+	var (
+		events *dashboard.Events
+		output io.Writer = os.Stdout
+	)
+	if config.TUI {
+		events = dashboard.NewEvents(dashboardEvents)
+		logrus.AddHook(events)
+		logrus.SetOutput(io.Discard)
+		output = io.Discard
+
+		// Logs are displayed again once the dashboard is gone, so that the
+		// error the application fails with isn't discarded
+		defer logrus.SetOutput(os.Stdout)
+	}
+
+	// Epoch history stores and report writers are created before any goroutine
+	// is started, so that nothing keeps running when one of them fails
+	stores := make(map[string]*history.Store, len(config.Networks))
+	if config.HistoryDir != "" {
+		for _, network := range config.Networks {
+			store, err := history.Open(filepath.Join(config.HistoryDir, network.Name))
+			if err != nil {
+				return err
+			}
+			stores[network.Name] = store
+		}
+	}
+	writers := make(map[string]*report.Writer, len(config.Networks))
+	if config.Report.Dir != "" {
+		options := make([]report.Option, 0)
+		if config.Report.Webhook != "" {
+			options = append(options, report.WithWebhook(alert.NewWebhook(config.Report.Webhook)))
+		}
+		for _, network := range config.Networks {
+			writer, err := report.NewWriter(filepath.Join(config.Report.Dir, network.Name), options...)
+			if err != nil {
+				return err
+			}
+			writers[network.Name] = writer
+		}
+	}
 
 	// Handle signals via context
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
@@ -52,35 +100,158 @@ func RunFunc(cCtx *cli.Context) error {
 	errg, ctx := errgroup.WithContext(ctx)
 
 	//
-	// Validator Watcher
+	// Alerts
 	//
-	logrus.Infof("connecting to node %s", node)
+	var alerts *alert.Manager
+	if config.Alert.Webhook != "" {
+		logrus.Infof("sending alerts to %s", config.Alert.Webhook)
 
-	client := near.NewClient(node)
+		alerts = alert.NewManager(
+			[]alert.Notifier{alert.NewWebhook(config.Alert.Webhook)},
+			alert.WithRepeatInterval(config.Alert.RepeatInterval),
+		)
+		errg.Go(func() error {
+			return alerts.Start(ctx)
+		})
+	}
+
+	//
+	// Reports delivery
+	//
+	for _, writer := range writers {
+		writer := writer
+		errg.Go(func() error {
+			return writer.Start(ctx)
+		})
+	}
 
+	//
+	// Validator Watchers
+	//
 	registry := prometheus.NewRegistry()
-	metrics := metrics.New(namespace)
-	metrics.Register(registry)
+	metrics.RegisterRuntime(registry)
 
-	watcher := watcher.New(client, metrics, &watcher.Config{
-		Writer:          os.Stdout,
-		TrackedAccounts: validators,
-		RefreshRate:     refreshRate,
-	})
+	watchers := make(map[string]*watcher.Watcher, len(config.Networks))
+	readyProbes := make(map[string]HealthProbe, len(config.Networks))
+	liveProbes := make(map[string]HealthProbe, len(config.Networks))
+	sources := make(map[string]api.Source, len(config.Networks))
+	screens := make(map[string]dashboard.Source, len(config.Networks))
+	for _, network := range config.Networks {
+		logrus.Infof("connecting to %s nodes %s", network.Name, strings.Join(near.EndpointNames(network.Nodes), ", "))
+
+		// Every metric of the network is labelled with its name
+		metrics := metrics.New(config.Namespace)
+		metrics.Register(prometheus.WrapRegistererWith(prometheus.Labels{"network": network.Name}, registry))
+
+		client := near.NewClient(
+			network.Nodes,
+			near.WithRecoveryDelay(network.NodeRecoveryDelay),
+			near.WithRequestObserver(metrics.ObserveRPCRequest),
+		)
+
+		// Reference nodes are labelled with their redacted url, as their
+		// endpoint in the rpc metrics
+		names := near.EndpointNames(network.ReferenceNodes)
+		references := make(map[string]*near.Client, len(network.ReferenceNodes))
+		for i, node := range network.ReferenceNodes {
+			references[names[i]] = near.NewClient(
+				[]string{node},
+				near.WithRecoveryDelay(network.NodeRecoveryDelay),
+				near.WithRequestObserver(metrics.ObserveRPCRequest),
+			)
+		}
+
+		tracking := network.Tracking(config.Alert.UptimeThreshold)
+		watcher := watcher.New(client, metrics, &watcher.Config{
+			Network:         network.Name,
+			Writer:          output,
+			TrackedAccounts: tracking.TrackedAccounts,
+			Validators:      tracking.Validators,
+			RefreshRate:     network.RefreshRate,
+			CollectTimeout:  config.CollectTimeout,
+			References:      references,
+			Health: watcher.HealthThresholds{
+				MaxCollectionAge: config.Health.MaxCollectionAge,
+				MaxBlockAge:      config.Health.MaxBlockAge,
+				MaxFailures:      config.Health.MaxFailures,
+				MaxCycleAge:      config.Health.MaxCycleAge,
+			},
+			History:              stores[network.Name],
+			Reports:              writers[network.Name],
+			Alerts:               alerts,
+			AlertUptimeThreshold: tracking.AlertUptimeThreshold,
+		})
+		errg.Go(func() error {
+			return watcher.Start(ctx)
+		})
+
+		watchers[network.Name] = watcher
+		readyProbes[network.Name] = watcher.Readiness
+		liveProbes[network.Name] = watcher.Liveness
+		sources[network.Name] = watcher
+		screens[network.Name] = watcher
+	}
+
+	//
+	// Dashboard
+	//
+	if config.TUI {
+		dashboard := dashboard.New(os.Stdout, screens,
+			dashboard.WithEvents(events),
+			dashboard.WithTopValidators(config.TUITopValidators),
+		)
+		errg.Go(func() error {
+			return dashboard.Run(ctx)
+		})
+	}
+
+	//
+	// Configuration reload
+	//
+	reload := func() error {
+		config, err := LoadConfig(cCtx)
+		if err != nil {
+			return err
+		}
+		for _, network := range config.Networks {
+			watcher, ok := watchers[network.Name]
+			if !ok {
+				logrus.Warnf("network %s can't be added without restart", network.Name)
+				continue
+			}
+			watcher.Reload(network.Tracking(config.Alert.UptimeThreshold))
+		}
+		return nil
+	}
 	errg.Go(func() error {
-		return watcher.Start(ctx)
+		hup := make(chan os.Signal, 1)
+		signal.Notify(hup, syscall.SIGHUP)
+		defer signal.Stop(hup)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-hup:
+				logrus.Info("received SIGHUP, reloading configuration")
+				if err := reload(); err != nil {
+					logrus.WithError(err).Error("failed to reload configuration")
+				}
+			}
+		}
 	})
 
 	//
 	// HTTP server
 	//
-	logrus.Infof("starting HTTP server on %s", httpAddr)
-	readyProbe := watcher.IsSynced
+	logrus.Infof("starting HTTP server on %s", config.HTTPAddr)
 	httpServer := NewHTTPServer(
-		httpAddr,
-		WithReadyProbe(readyProbe),
-		WithLiveProbe(upProbe),
+		config.HTTPAddr,
+		WithReadyProbes(readyProbes),
+		WithLiveProbes(liveProbes),
 		WithMetrics(registry),
+		WithHandler(api.Prefix, api.New(sources)),
+		WithReload(config.ReloadToken, reload),
 	)
 	errg.Go(func() error {
 		return httpServer.Run()
@@ -0,0 +1,432 @@
+package dashboard
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/kilnfi/near-validator-watcher/pkg/watcher"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultTopValidators = 10
+	refreshRate          = time.Second
+	progressBarWidth     = 40
+
+	// ANSI escape sequences
+	enterScreen = "\033[?1049h\033[?25l"
+	leaveScreen = "\033[?25h\033[?1049l"
+	cursorHome  = "\033[H"
+	clearLine   = "\033[K"
+	clearBelow  = "\033[J"
+)
+
+var yoctoUnit = decimal.NewFromInt(10).Pow(decimal.NewFromInt(24))
+
+// Source provides the data of a watched network.
+type Source interface {
+	Snapshot() *watcher.Snapshot
+}
+
+// Dashboard draws the last collected data of the watched networks on a full
+// screen terminal. The screen is redrawn every second, so that the data of
+// each collection cycle is displayed as soon as it is stored.
+type Dashboard struct {
+	writer        io.Writer
+	sources       map[string]Source
+	events        *Events
+	topValidators int
+}
+
+type Option func(*Dashboard)
+
+// WithEvents displays the recent events kept by the hook.
+func WithEvents(events *Events) Option {
+	return func(d *Dashboard) {
+		d.events = events
+	}
+}
+
+// WithTopValidators sets how many validators are displayed by rank, tracked
+// validators being always displayed.
+func WithTopValidators(n int) Option {
+	return func(d *Dashboard) {
+		d.topValidators = n
+	}
+}
+
+func New(writer io.Writer, sources map[string]Source, options ...Option) *Dashboard {
+	dashboard := &Dashboard{
+		writer:        writer,
+		sources:       sources,
+		topValidators: defaultTopValidators,
+	}
+
+	for _, option := range options {
+		option(dashboard)
+	}
+
+	return dashboard
+}
+
+// Run draws the dashboard until the context is done, the terminal is then
+// restored.
+func (d *Dashboard) Run(ctx context.Context) error {
+	fmt.Fprint(d.writer, enterScreen)
+	defer fmt.Fprint(d.writer, leaveScreen)
+
+	ticker := time.NewTicker(refreshRate)
+	defer ticker.Stop()
+
+	for {
+		d.draw(time.Now())
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// draw writes the whole frame at once, overwriting the previous one line by
+// line to avoid flickering.
+func (d *Dashboard) draw(now time.Time) {
+	var buf bytes.Buffer
+	buf.WriteString(cursorHome)
+	for _, line := range d.Render(now) {
+		buf.WriteString(line)
+		buf.WriteString(clearLine)
+		buf.WriteString("\n")
+	}
+	buf.WriteString(clearBelow)
+
+	d.writer.Write(buf.Bytes())
+}
+
+// Render returns the lines of the dashboard.
+func (d *Dashboard) Render(now time.Time) []string {
+	lines := []string{
+		color.New(color.Bold).Sprint("NEAR Validator Watcher") + "  " + now.UTC().Format("2006-01-02 15:04:05 UTC"),
+	}
+
+	networks := make([]string, 0, len(d.sources))
+	for network := range d.sources {
+		networks = append(networks, network)
+	}
+	sort.Strings(networks)
+
+	for _, network := range networks {
+		lines = append(lines, "")
+		lines = append(lines, d.renderNetwork(network, d.sources[network].Snapshot(), now)...)
+	}
+
+	if d.events != nil {
+		lines = append(lines, "", color.New(color.Bold).Sprint("Recent events"))
+		for _, event := range d.events.Recent() {
+			lines = append(lines, renderEvent(event))
+		}
+	}
+
+	return lines
+}
+
+func (d *Dashboard) renderNetwork(network string, s *watcher.Snapshot, now time.Time) []string {
+	title := color.MagentaString("[%s]", network)
+	if s == nil {
+		return []string{title + " waiting for data..."}
+	}
+
+	var (
+		elapsed, remaining = s.EpochProgress()
+		blocks, chunks     = s.KickoutThresholds()
+		endorsements       = s.EndorsementsKickoutThreshold()
+	)
+
+	header := fmt.Sprintf("%s %s %s  %d validators  protocol %d  updated %s ago",
+		title,
+		color.YellowString("#%d", s.Status.SyncInfo.LatestBlockHeight),
+		color.CyanString("epoch %d", s.Validators.EpochHeight),
+		len(s.Validators.CurrentValidators),
+		s.ProtocolConfig.ProtocolVersion,
+		now.Sub(s.CollectedAt).Round(time.Second),
+	)
+	if s.Status.SyncInfo.Syncing {
+		header += " " + color.RedString("(syncing)")
+	}
+
+	var progress float64
+	if s.ProtocolConfig.EpochLength > 0 {
+		progress = float64(elapsed) / float64(s.ProtocolConfig.EpochLength)
+	}
+
+	thresholds := fmt.Sprintf("kickout thresholds: blocks %d%%, chunks %d%%, endorsements %d%%", blocks, chunks, endorsements)
+	if s.SeatPrice != nil {
+		thresholds = fmt.Sprintf("seat price %s NEAR  %s", formatNEAR(*s.SeatPrice), thresholds)
+	}
+
+	lines := []string{
+		header,
+		fmt.Sprintf("Epoch %s %5.1f%%  %d blocks remaining", progressBar(progress, progressBarWidth), 100*progress, remaining),
+		thresholds,
+		"",
+	}
+
+	table := newTable(
+		column{title: ""},
+		column{title: "RANK", right: true},
+		column{title: "VALIDATOR"},
+		column{title: "STAKE (NEAR)", right: true},
+		column{title: "BLOCKS", right: true},
+		column{title: "CHUNKS", right: true},
+		column{title: "ENDORSEMENTS", right: true},
+		column{title: "SEAT MARGIN", right: true},
+		column{title: "NEXT EPOCH"},
+	)
+	for _, v := range d.rows(s) {
+		var (
+			marker = ""
+			rank   = "-"
+			stake  = cell{text: "-"}
+			margin = cell{text: "-"}
+			name   = cell{text: s.DisplayName(v.AccountId)}
+		)
+		if v.tracked {
+			marker = "*"
+			name.color = color.New(color.Bold)
+		}
+		if v.rank > 0 {
+			rank = fmt.Sprint(v.rank)
+			stake.text = formatNEAR(v.Stake)
+			if s.SeatPrice != nil {
+				margin = marginCell(v.Stake.Sub(*s.SeatPrice))
+			}
+		}
+
+		table.add(
+			cell{text: marker},
+			cell{text: rank},
+			name,
+			stake,
+			uptimeCell(v.rank > 0, v.NumProducedBlocks, v.NumExpectedBlocks, blocks),
+			uptimeCell(v.rank > 0, v.NumProducedChunks, v.NumExpectedChunks, chunks),
+			uptimeCell(v.rank > 0, v.NumProducedEndorsements, v.NumExpectedEndorsements, endorsements),
+			margin,
+			nextEpochCell(s, v.AccountId),
+		)
+	}
+
+	return append(lines, table.lines()...)
+}
+
+// row is a validator displayed on the dashboard, the epoch stats are only set
+// for current validators, which have a rank.
+type row struct {
+	near.CurrentEpochValidatorInfo
+	rank    int
+	tracked bool
+}
+
+// rows returns the top validators by stake, then the tracked validators
+// outside of the top ones.
+func (d *Dashboard) rows(s *watcher.Snapshot) []row {
+	current := make([]near.CurrentEpochValidatorInfo, len(s.Validators.CurrentValidators))
+	copy(current, s.Validators.CurrentValidators)
+	sort.SliceStable(current, func(i, j int) bool {
+		return current[i].Stake.GreaterThan(current[j].Stake)
+	})
+
+	var (
+		rows      = make([]row, 0, d.topValidators+len(s.TrackedAccounts))
+		isCurrent = make(map[string]bool, len(current))
+	)
+	for i, v := range current {
+		isCurrent[v.AccountId] = true
+		tracked := s.IsTracked(v.AccountId)
+		if i < d.topValidators || tracked {
+			rows = append(rows, row{CurrentEpochValidatorInfo: v, rank: i + 1, tracked: tracked})
+		}
+	}
+
+	// Tracked accounts which aren't current validators, e.g. kicked out
+	for _, account := range s.TrackedAccounts {
+		if !isCurrent[account] {
+			v := near.CurrentEpochValidatorInfo{}
+			v.AccountId = account
+			rows = append(rows, row{CurrentEpochValidatorInfo: v, tracked: true})
+		}
+	}
+
+	return rows
+}
+
+func uptimeCell(current bool, produced int64, expected int64, threshold int) cell {
+	if !current || expected == 0 {
+		return cell{text: "-"}
+	}
+
+	uptime := 100 * float64(produced) / float64(expected)
+	c := cell{text: formatPercent(uptime), color: color.New(color.FgGreen)}
+	if uptime < float64(threshold) {
+		c.color = color.New(color.FgRed)
+	}
+	return c
+}
+
+func marginCell(margin decimal.Decimal) cell {
+	c := cell{text: formatNEAR(margin), color: color.New(color.FgGreen)}
+	if margin.IsPositive() {
+		c.text = "+" + c.text
+	}
+	if margin.IsNegative() {
+		c.color = color.New(color.FgRed)
+	}
+	return c
+}
+
+func nextEpochCell(s *watcher.Snapshot, accountID string) cell {
+	for _, v := range s.Validators.NextValidators {
+		if v.AccountId == accountID {
+			return cell{text: "yes", color: color.New(color.FgGreen)}
+		}
+	}
+	for _, v := range s.Validators.CurrentProposals {
+		if v.AccountId == accountID && !v.Stake.IsZero() {
+			return cell{text: "proposed", color: color.New(color.FgYellow)}
+		}
+	}
+	return cell{text: "no", color: color.New(color.FgRed)}
+}
+
+func renderEvent(event Event) string {
+	level := strings.ToUpper(event.Level.String())
+	switch event.Level {
+	case logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel:
+		level = color.RedString("%-7s", level)
+	case logrus.WarnLevel:
+		level = color.YellowString("%-7s", level)
+	default:
+		level = color.CyanString("%-7s", level)
+	}
+	return fmt.Sprintf("%s %s %s", event.Time.UTC().Format("15:04:05"), level, event)
+}
+
+// progressBar returns a bar filled with the progress, between 0 and 1.
+func progressBar(progress float64, width int) string {
+	filled := int(progress * float64(width))
+	if filled < 0 {
+		filled = 0
+	}
+	if filled > width {
+		filled = width
+	}
+	return "[" + color.GreenString(strings.Repeat("█", filled)) + strings.Repeat("░", width-filled) + "]"
+}
+
+// formatNEAR returns the yoctoNEAR amount in NEAR, rounded and with thousands
+// separators.
+func formatNEAR(yocto decimal.Decimal) string {
+	var (
+		amount = yocto.Div(yoctoUnit).Round(0)
+		digits = amount.Abs().String()
+		sign   = ""
+	)
+	if amount.IsNegative() {
+		sign = "-"
+	}
+
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	return sign + b.String()
+}
+
+func formatPercent(f float64) string {
+	if f == float64(int(f)) {
+		return fmt.Sprintf("%.0f%%", f)
+	}
+	return fmt.Sprintf("%.2f%%", f)
+}
+
+// cell is a table cell, padded before being colored so that escape sequences
+// don't break the alignment.
+type cell struct {
+	text  string
+	color *color.Color
+}
+
+type column struct {
+	title string
+	right bool
+}
+
+type table struct {
+	columns []column
+	rows    [][]cell
+}
+
+func newTable(columns ...column) *table {
+	return &table{columns: columns}
+}
+
+func (t *table) add(cells ...cell) {
+	t.rows = append(t.rows, cells)
+}
+
+func (t *table) lines() []string {
+	widths := make([]int, len(t.columns))
+	for i, c := range t.columns {
+		widths[i] = len([]rune(c.title))
+	}
+	for _, r := range t.rows {
+		for i, c := range r {
+			if n := len([]rune(c.text)); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	header := make([]string, len(t.columns))
+	for i, c := range t.columns {
+		header[i] = t.pad(i, c.title, widths[i])
+	}
+	lines := []string{color.New(color.Faint).Sprint(strings.Join(header, "  "))}
+
+	for _, r := range t.rows {
+		cells := make([]string, len(r))
+		for i, c := range r {
+			cells[i] = t.pad(i, c.text, widths[i])
+			if c.color != nil {
+				cells[i] = c.color.Sprint(cells[i])
+			}
+		}
+		lines = append(lines, strings.Join(cells, "  "))
+	}
+
+	return lines
+}
+
+// pad aligns the text in its column, the last column is only padded when
+// right aligned to avoid trailing spaces.
+func (t *table) pad(i int, s string, width int) string {
+	padding := strings.Repeat(" ", width-len([]rune(s)))
+	if t.columns[i].right {
+		return padding + s
+	}
+	if i == len(t.columns)-1 {
+		return s
+	}
+	return s + padding
+}
@@ -0,0 +1,114 @@
+package dashboard
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/kilnfi/near-validator-watcher/pkg/watcher"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSource struct {
+	snapshot *watcher.Snapshot
+}
+
+func (s testSource) Snapshot() *watcher.Snapshot {
+	return s.snapshot
+}
+
+func validator(accountID string, stake int64, produced int64, expected int64) near.CurrentEpochValidatorInfo {
+	v := near.CurrentEpochValidatorInfo{
+		NumProducedBlocks: produced,
+		NumExpectedBlocks: expected,
+		NumProducedChunks: produced,
+		NumExpectedChunks: expected,
+	}
+	v.AccountId = accountID
+	v.Stake = decimal.NewFromInt(stake).Mul(yoctoUnit)
+	return v
+}
+
+func TestDashboard(t *testing.T) {
+	color.NoColor = true
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	seatPrice := decimal.NewFromInt(1000).Mul(yoctoUnit)
+
+	snapshot := &watcher.Snapshot{
+		Network:         "mainnet",
+		TrackedAccounts: []string{"kiln.poolv1.near", "gone.poolv1.near"},
+		CollectedAt:     now.Add(-3 * time.Second),
+		SeatPrice:       &seatPrice,
+	}
+	snapshot.Status.SyncInfo.LatestBlockHeight = 1250
+	snapshot.ProtocolConfig.EpochLength = 1000
+	snapshot.ProtocolConfig.BlockProducerKickoutThreshold = 80
+	snapshot.ProtocolConfig.ChunkProducerKickoutThreshold = 80
+	snapshot.Validators.EpochHeight = 42
+	snapshot.Validators.EpochStartHeight = 1000
+	snapshot.Validators.CurrentValidators = []near.CurrentEpochValidatorInfo{
+		validator("small.poolv1.near", 1000, 10, 10),
+		validator("big.poolv1.near", 3000, 10, 10),
+		validator("kiln.poolv1.near", 500, 7, 10),
+	}
+	snapshot.Validators.NextValidators = append(snapshot.Validators.NextValidators, struct {
+		near.Validator
+		Shards []int `json:"shards"`
+	}{Validator: near.Validator{AccountId: "big.poolv1.near"}})
+
+	events := NewEvents(2)
+	logger := logrus.New()
+	logger.AddHook(events)
+	logger.SetOutput(&strings.Builder{})
+	logger.Debug("hidden")
+	logger.Info("first")
+	logger.WithField("account_id", "kiln.poolv1.near").WithError(errors.New("boom")).Warn("second")
+	logger.Error("third")
+
+	dashboard := New(&strings.Builder{}, map[string]Source{
+		"mainnet": testSource{snapshot},
+		"testnet": testSource{},
+	}, WithTopValidators(1), WithEvents(events))
+
+	assert.Equal(t, []string{
+		"NEAR Validator Watcher  2024-01-01 12:00:00 UTC",
+		"",
+		"[mainnet] #1250 epoch 42  3 validators  protocol 0  updated 3s ago",
+		"Epoch [██████████░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░]  25.0%  750 blocks remaining",
+		"seat price 1,000 NEAR  kickout thresholds: blocks 80%, chunks 80%, endorsements 90%",
+		"",
+		"   RANK  VALIDATOR  STAKE (NEAR)  BLOCKS  CHUNKS  ENDORSEMENTS  SEAT MARGIN  NEXT EPOCH",
+		"      1  big               3,000    100%    100%             -       +2,000  yes",
+		"*     3  kiln                500     70%     70%             -         -500  no",
+		"*     -  gone                  -       -       -             -            -  no",
+		"",
+		"[testnet] waiting for data...",
+		"",
+		"Recent events",
+		"12:00:00 ERROR   third",
+		"12:00:00 WARNING second account_id=kiln.poolv1.near error=boom",
+	}, replaceEventTimes(dashboard.Render(now)))
+
+	t.Run("Format NEAR", func(t *testing.T) {
+		assert.Equal(t, "0", formatNEAR(decimal.Zero))
+		assert.Equal(t, "999", formatNEAR(decimal.NewFromInt(999).Mul(yoctoUnit)))
+		assert.Equal(t, "1,234,568", formatNEAR(decimal.NewFromFloat(1234567.8).Mul(yoctoUnit)))
+		assert.Equal(t, "-12,000", formatNEAR(decimal.NewFromInt(-12000).Mul(yoctoUnit)))
+	})
+}
+
+// replaceEventTimes replaces the time of the events, which are logged now, by
+// the rendering time.
+func replaceEventTimes(lines []string) []string {
+	for i, line := range lines {
+		if strings.Contains(line, "ERROR") || strings.Contains(line, "WARNING") {
+			lines[i] = "12:00:00" + line[len("15:04:05"):]
+		}
+	}
+	return lines
+}
@@ -0,0 +1,93 @@
+package dashboard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Event is a log entry displayed on the dashboard.
+type Event struct {
+	Time    time.Time
+	Level   logrus.Level
+	Message string
+	Fields  logrus.Fields
+}
+
+// String returns the message followed by the fields sorted by key.
+func (e Event) String() string {
+	keys := make([]string, 0, len(e.Fields))
+	for key := range e.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys)+1)
+	parts = append(parts, e.Message)
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%v", key, e.Fields[key]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Events is a logrus hook keeping the most recent log entries, from info
+// level, so that they can be displayed while the logs are hidden.
+type Events struct {
+	mu     sync.Mutex
+	size   int
+	events []Event
+}
+
+func NewEvents(size int) *Events {
+	return &Events{
+		size:   size,
+		events: make([]Event, 0, size),
+	}
+}
+
+func (e *Events) Levels() []logrus.Level {
+	return []logrus.Level{
+		logrus.PanicLevel,
+		logrus.FatalLevel,
+		logrus.ErrorLevel,
+		logrus.WarnLevel,
+		logrus.InfoLevel,
+	}
+}
+
+func (e *Events) Fire(entry *logrus.Entry) error {
+	fields := make(logrus.Fields, len(entry.Data))
+	for key, value := range entry.Data {
+		fields[key] = value
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.events = append(e.events, Event{
+		Time:    entry.Time,
+		Level:   entry.Level,
+		Message: entry.Message,
+		Fields:  fields,
+	})
+	if len(e.events) > e.size {
+		e.events = e.events[len(e.events)-e.size:]
+	}
+	return nil
+}
+
+// Recent returns the kept events, the most recent first.
+func (e *Events) Recent() []Event {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	recent := make([]Event, 0, len(e.events))
+	for i := len(e.events) - 1; i >= 0; i-- {
+		recent = append(recent, e.events[i])
+	}
+	return recent
+}
@@ -0,0 +1,143 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+)
+
+var ErrNotFound = errors.New("epoch not found")
+
+// Epoch holds the final stats of the validators of an epoch.
+type Epoch struct {
+	EpochHeight      int64 `json:"epoch_height"`
+	EpochStartHeight int64 `json:"epoch_start_height"`
+	// LastBlockHeight is the height the stats were fetched at
+	LastBlockHeight int64                            `json:"last_block_height"`
+	RecordedAt      time.Time                        `json:"recorded_at"`
+	Validators      []near.CurrentEpochValidatorInfo `json:"validators"`
+}
+
+// Validator returns the stats of the validator during the epoch.
+func (e Epoch) Validator(accountID string) (near.CurrentEpochValidatorInfo, bool) {
+	for _, v := range e.Validators {
+		if v.AccountId == accountID {
+			return v, true
+		}
+	}
+	return near.CurrentEpochValidatorInfo{}, false
+}
+
+// Store persists epochs as JSON files in a directory, one file per epoch.
+type Store struct {
+	dir string
+	mu  sync.RWMutex
+}
+
+const filePrefix, fileSuffix = "epoch-", ".json"
+
+// Open creates the directory of the store if needed.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+func (s *Store) path(epochHeight int64) string {
+	return filepath.Join(s.dir, filePrefix+strconv.FormatInt(epochHeight, 10)+fileSuffix)
+}
+
+// Save records the epoch, replacing any previous record of the same height.
+func (s *Store) Save(epoch Epoch) error {
+	content, err := json.MarshalIndent(epoch, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Write to a temporary file first so that a crash never leaves a partial record
+	tmp, err := os.CreateTemp(s.dir, filePrefix+"*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path(epoch.EpochHeight))
+}
+
+// Get returns the epoch of the given height, or ErrNotFound.
+func (s *Store) Get(epochHeight int64) (Epoch, error) {
+	var epoch Epoch
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	content, err := os.ReadFile(s.path(epochHeight))
+	if errors.Is(err, os.ErrNotExist) {
+		return epoch, ErrNotFound
+	}
+	if err != nil {
+		return epoch, err
+	}
+
+	if err := json.Unmarshal(content, &epoch); err != nil {
+		return epoch, fmt.Errorf("failed to decode epoch %d: %w", epochHeight, err)
+	}
+	return epoch, nil
+}
+
+// Has returns whether the epoch of the given height is recorded.
+func (s *Store) Has(epochHeight int64) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, err := os.Stat(s.path(epochHeight))
+	return err == nil
+}
+
+// Heights returns the heights of the recorded epochs in ascending order.
+func (s *Store) Heights() ([]int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	heights := make([]int64, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, filePrefix) || !strings.HasSuffix(name, fileSuffix) {
+			continue
+		}
+		height, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(name, filePrefix), fileSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		heights = append(heights, height)
+	}
+
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	return heights, nil
+}
@@ -0,0 +1,53 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := Open(dir)
+	require.NoError(t, err)
+
+	_, err = store.Get(2000)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	validator := near.CurrentEpochValidatorInfo{NumProducedBlocks: 90, NumExpectedBlocks: 100}
+	validator.AccountId = "kiln.poolv1.near"
+	validator.Stake = decimal.RequireFromString("23052051536090128427622637823")
+
+	for _, height := range []int64{2001, 2000, 1999} {
+		require.NoError(t, store.Save(Epoch{
+			EpochHeight:      height,
+			EpochStartHeight: height * 43200,
+			RecordedAt:       time.Date(2023, 10, 18, 13, 35, 36, 0, time.UTC),
+			Validators:       []near.CurrentEpochValidatorInfo{validator},
+		}))
+	}
+
+	// Records survive a restart
+	store, err = Open(dir)
+	require.NoError(t, err)
+
+	heights, err := store.Heights()
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1999, 2000, 2001}, heights)
+	assert.True(t, store.Has(2000))
+	assert.False(t, store.Has(2002))
+
+	epoch, err := store.Get(2000)
+	require.NoError(t, err)
+	assert.Equal(t, int64(86400000), epoch.EpochStartHeight)
+
+	v, ok := epoch.Validator("kiln.poolv1.near")
+	require.True(t, ok)
+	assert.Equal(t, int64(90), v.NumProducedBlocks)
+	assert.True(t, validator.Stake.Equal(v.Stake))
+}
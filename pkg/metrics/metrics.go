@@ -6,26 +6,78 @@ import (
 )
 
 type Metrics struct {
-	BlockNumber                   prometheus.Gauge
-	ChainID                       *prometheus.GaugeVec
-	CurrentProposals              *prometheus.GaugeVec
-	EpochLength                   prometheus.Gauge
-	EpochStartHeight              prometheus.Gauge
-	NextValidatorStake            *prometheus.GaugeVec
-	PrevEpochKickout              *prometheus.GaugeVec
-	ProtocolVersion               prometheus.Gauge
-	SeatPrice                     prometheus.Gauge
-	SyncingDesc                   prometheus.Gauge
-	ValidatorExpectedBlocks       *prometheus.GaugeVec
-	ValidatorExpectedChunks       *prometheus.GaugeVec
-	ValidatorExpectedEndorsements *prometheus.GaugeVec
-	ValidatorProducedBlocks       *prometheus.GaugeVec
-	ValidatorProducedChunks       *prometheus.GaugeVec
-	ValidatorProducedEndorsements *prometheus.GaugeVec
-	ValidatorSlashed              *prometheus.GaugeVec
-	ValidatorStake                *prometheus.GaugeVec
-	ValidatorRank                 *prometheus.GaugeVec
-	VersionBuild                  *prometheus.GaugeVec
+	BlockNumber                        prometheus.Gauge
+	BlockProducerKickoutThreshold      prometheus.Gauge
+	BlocksAuthored                     *prometheus.CounterVec
+	ChainID                            *prometheus.GaugeVec
+	ChunkProducerKickoutThreshold      prometheus.Gauge
+	ChunkValidatorOnlyKickoutThreshold prometheus.Gauge
+	CollectionErrors                   *prometheus.CounterVec
+	CurrentProposals                   *prometheus.GaugeVec
+	EpochLength                        prometheus.Gauge
+	EpochRemainingBlocks               prometheus.Gauge
+	EpochStartHeight                   prometheus.Gauge
+	LastSuccessTimestamp               *prometheus.GaugeVec
+	MissedBlocks                       *prometheus.CounterVec
+	MissedChunks                       *prometheus.CounterVec
+	NextSeatPrice                      prometheus.Gauge
+	NextValidatorStake                 *prometheus.GaugeVec
+	NodeValidatorKeyMismatch           *prometheus.GaugeVec
+	PrevEpochKickout                   *prometheus.GaugeVec
+	PrevEpochKickoutExpected           *prometheus.GaugeVec
+	PrevEpochKickoutProduced           *prometheus.GaugeVec
+	PrevEpochKickoutProtocolVersion    *prometheus.GaugeVec
+	PrevEpochKickoutStake              *prometheus.GaugeVec
+	PrevEpochKickoutStakeThreshold     *prometheus.GaugeVec
+	ProposalsSeatPrice                 prometheus.Gauge
+	ProtocolVersion                    prometheus.Gauge
+	ReferenceNodeFork                  *prometheus.GaugeVec
+	ReferenceNodeHeightLag             *prometheus.GaugeVec
+	ReferenceNodeTimeLag               *prometheus.GaugeVec
+	RPCEndpointActive                  *prometheus.GaugeVec
+	RPCEndpointFailures                *prometheus.CounterVec
+	RPCEndpointHealthy                 *prometheus.GaugeVec
+	RPCEndpointRequests                *prometheus.CounterVec
+	RPCErrors                          *prometheus.CounterVec
+	RPCRequestDuration                 *prometheus.HistogramVec
+	RPCRequests                        *prometheus.CounterVec
+	SeatPrice                          prometheus.Gauge
+	ShardChunksIncluded                *prometheus.CounterVec
+	ShardChunksMissed                  *prometheus.CounterVec
+	SkippedBlocks                      prometheus.Counter
+	StakingPoolAccounts                *prometheus.GaugeVec
+	StakingPoolAPY                     *prometheus.GaugeVec
+	StakingPoolEpochReward             *prometheus.GaugeVec
+	StakingPoolInfo                    *prometheus.GaugeVec
+	StakingPoolNetAPY                  *prometheus.GaugeVec
+	StakingPoolPaused                  *prometheus.GaugeVec
+	StakingPoolRewardFee               *prometheus.GaugeVec
+	StakingPoolStakingKeyMismatch      *prometheus.GaugeVec
+	StakingPoolTotalStakedBalance      *prometheus.GaugeVec
+	SyncingDesc                        prometheus.Gauge
+	ValidatorBlocksProjectedUptime     *prometheus.GaugeVec
+	ValidatorBlocksTolerableMisses     *prometheus.GaugeVec
+	ValidatorChunkValidatorOnly        *prometheus.GaugeVec
+	ValidatorChunksProjectedUptime     *prometheus.GaugeVec
+	ValidatorChunksTolerableMisses     *prometheus.GaugeVec
+	ValidatorExpectedBlocks            *prometheus.GaugeVec
+	ValidatorExpectedChunks            *prometheus.GaugeVec
+	ValidatorExpectedEndorsements      *prometheus.GaugeVec
+	ValidatorInNextEpoch               *prometheus.GaugeVec
+	ValidatorNextStakeDelta            *prometheus.GaugeVec
+	ValidatorProducedBlocks            *prometheus.GaugeVec
+	ValidatorProducedChunks            *prometheus.GaugeVec
+	ValidatorProducedEndorsements      *prometheus.GaugeVec
+	ValidatorProposalPending           *prometheus.GaugeVec
+	ValidatorSeatPriceMargin           *prometheus.GaugeVec
+	ValidatorShardExpectedChunks       *prometheus.GaugeVec
+	ValidatorShardExpectedEndorsements *prometheus.GaugeVec
+	ValidatorShardProducedChunks       *prometheus.GaugeVec
+	ValidatorShardProducedEndorsements *prometheus.GaugeVec
+	ValidatorSlashed                   *prometheus.GaugeVec
+	ValidatorStake                     *prometheus.GaugeVec
+	ValidatorRank                      *prometheus.GaugeVec
+	VersionBuild                       *prometheus.GaugeVec
 }
 
 func New(namespace string) *Metrics {
@@ -35,12 +87,39 @@ func New(namespace string) *Metrics {
 			Name:      "block_number",
 			Help:      "The number of most recent block",
 		}),
+		BlockProducerKickoutThreshold: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "block_producer_kickout_threshold",
+			Help:      "Minimum blocks uptime (in percent) below which validators are kicked out",
+		}),
+		BlocksAuthored: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "blocks_authored_total",
+			Help:      "Number of blocks authored by the validator since the watcher started"},
+			[]string{"account_id", "tracked"},
+		),
 		ChainID: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: namespace,
 			Name:      "chain_id",
 			Help:      "Near chain id"},
 			[]string{"chain_id"},
 		),
+		ChunkProducerKickoutThreshold: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "chunk_producer_kickout_threshold",
+			Help:      "Minimum chunks uptime (in percent) below which validators are kicked out",
+		}),
+		ChunkValidatorOnlyKickoutThreshold: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "chunk_validator_only_kickout_threshold",
+			Help:      "Minimum endorsements uptime (in percent) below which chunk validators only are kicked out",
+		}),
+		CollectionErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "collection_errors_total",
+			Help:      "Number of failed collections by collector, after retries"},
+			[]string{"collector"},
+		),
 		CurrentProposals: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: namespace,
 			Name:      "current_proposals_stake",
@@ -52,38 +131,269 @@ func New(namespace string) *Metrics {
 			Name:      "epoch_length",
 			Help:      "Near epoch length as specified in the protocol",
 		}),
+		EpochRemainingBlocks: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "epoch_remaining_blocks",
+			Help:      "Number of block heights remaining before the end of the epoch",
+		}),
 		EpochStartHeight: prometheus.NewGauge(prometheus.GaugeOpts{
 			Namespace: namespace,
 			Name:      "epoch_start_height",
 			Help:      "Near epoch start height",
 		}),
+		LastSuccessTimestamp: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "last_success_timestamp",
+			Help:      "Unix timestamp of the last successful collection by collector"},
+			[]string{"collector"},
+		),
+		MissedBlocks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "missed_blocks_total",
+			Help:      "Number of blocks missed by the validator since the watcher started"},
+			[]string{"account_id", "tracked"},
+		),
+		MissedChunks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "missed_chunks_total",
+			Help:      "Number of chunks missed by the tracked validator per shard since the watcher started"},
+			[]string{"account_id", "shard_id"},
+		),
+		NextSeatPrice: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "next_seat_price",
+			Help:      "Validator seat price of the next epoch",
+		}),
 		NextValidatorStake: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: namespace,
 			Name:      "next_validator_stake",
 			Help:      "The next validators"},
 			[]string{"account_id", "public_key", "epoch_start_height", "tracked"},
 		),
+		NodeValidatorKeyMismatch: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "node_validator_key_mismatch",
+			Help:      "Whether the validator key of the node differs from the key registered in the validators (current, next) or the staking pool"},
+			[]string{"account_id", "source"},
+		),
 		PrevEpochKickout: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: namespace,
 			Name:      "prev_epoch_kickout",
 			Help:      "Near previous epoch kicked out validators"},
 			[]string{"account_id", "reason", "epoch_start_height", "tracked"},
 		),
+		PrevEpochKickoutExpected: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "prev_epoch_kickout_expected",
+			Help:      "Number of blocks, chunks or endorsements expected from the validator kicked out for not producing enough of them"},
+			[]string{"account_id", "reason"},
+		),
+		PrevEpochKickoutProduced: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "prev_epoch_kickout_produced",
+			Help:      "Number of blocks, chunks or endorsements produced by the validator kicked out for not producing enough of them"},
+			[]string{"account_id", "reason"},
+		),
+		PrevEpochKickoutProtocolVersion: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "prev_epoch_kickout_protocol_version",
+			Help:      "Protocol version of the validator kicked out for running a too old protocol version"},
+			[]string{"account_id", "reason"},
+		),
+		PrevEpochKickoutStake: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "prev_epoch_kickout_stake",
+			Help:      "Stake of the validator kicked out for not having enough stake"},
+			[]string{"account_id", "reason"},
+		),
+		PrevEpochKickoutStakeThreshold: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "prev_epoch_kickout_stake_threshold",
+			Help:      "Stake threshold the validator kicked out for not having enough stake was below"},
+			[]string{"account_id", "reason"},
+		),
+		ProposalsSeatPrice: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "proposals_seat_price",
+			Help:      "Expected validator seat price of the epoch after next based on current proposals",
+		}),
 		ProtocolVersion: prometheus.NewGauge(prometheus.GaugeOpts{
 			Namespace: namespace,
 			Name:      "protocol_version",
 			Help:      "Current protocol version deployed to the blockchain",
 		}),
+		ReferenceNodeFork: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "reference_node_fork",
+			Help:      "Whether the reference node has a different final block than the watched node at the same height"},
+			[]string{"reference"},
+		),
+		ReferenceNodeHeightLag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "reference_node_height_lag",
+			Help:      "Number of blocks the watched node is behind the reference node"},
+			[]string{"reference"},
+		),
+		ReferenceNodeTimeLag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "reference_node_time_lag_seconds",
+			Help:      "Time between the latest blocks of the reference node and of the watched node"},
+			[]string{"reference"},
+		),
+		RPCEndpointActive: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "rpc_endpoint_active",
+			Help:      "Whether the rpc endpoint served the last request"},
+			[]string{"endpoint"},
+		),
+		RPCEndpointFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rpc_endpoint_failures_total",
+			Help:      "Number of failed requests sent to the rpc endpoint"},
+			[]string{"endpoint"},
+		),
+		RPCEndpointHealthy: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "rpc_endpoint_healthy",
+			Help:      "Whether the last request sent to the rpc endpoint succeeded"},
+			[]string{"endpoint"},
+		),
+		RPCEndpointRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rpc_endpoint_requests_total",
+			Help:      "Number of requests sent to the rpc endpoint"},
+			[]string{"endpoint"},
+		),
+		RPCErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rpc_errors_total",
+			Help:      "Number of failed rpc requests by method and cause"},
+			[]string{"method", "endpoint", "cause", "status_code"},
+		),
+		RPCRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "rpc_request_duration_seconds",
+			Help:      "Latency of rpc requests by method",
+			Buckets:   prometheus.DefBuckets},
+			[]string{"method", "endpoint"},
+		),
+		RPCRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rpc_requests_total",
+			Help:      "Number of rpc requests by method"},
+			[]string{"method", "endpoint"},
+		),
 		SeatPrice: prometheus.NewGauge(prometheus.GaugeOpts{
 			Namespace: namespace,
 			Name:      "seat_price",
 			Help:      "Validator seat price",
 		}),
+		ShardChunksIncluded: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "shard_chunks_included_total",
+			Help:      "Number of blocks including a new chunk for the shard since the watcher started"},
+			[]string{"shard_id"},
+		),
+		ShardChunksMissed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "shard_chunks_missed_total",
+			Help:      "Number of blocks missing a new chunk for the shard since the watcher started"},
+			[]string{"shard_id"},
+		),
+		SkippedBlocks: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "skipped_blocks_total",
+			Help:      "Number of skipped block heights since the watcher started",
+		}),
+		StakingPoolAccounts: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "staking_pool_accounts",
+			Help:      "Number of accounts delegating to the staking pool"},
+			[]string{"account_id"},
+		),
+		StakingPoolAPY: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "staking_pool_apy",
+			Help:      "Annualised return (in percent) of the staking pool based on its reward of the last epoch"},
+			[]string{"account_id"},
+		),
+		StakingPoolEpochReward: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "staking_pool_epoch_reward",
+			Help:      "Reward distributed to the staking pool for the last epoch"},
+			[]string{"account_id"},
+		),
+		StakingPoolInfo: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "staking_pool_info",
+			Help:      "Owner and staking key of the staking pool"},
+			[]string{"account_id", "owner_id", "staking_key"},
+		),
+		StakingPoolNetAPY: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "staking_pool_net_apy",
+			Help:      "Annualised return (in percent) of the staking pool delegators after the reward fee"},
+			[]string{"account_id"},
+		),
+		StakingPoolPaused: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "staking_pool_paused",
+			Help:      "Whether staking is paused on the staking pool"},
+			[]string{"account_id"},
+		),
+		StakingPoolRewardFee: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "staking_pool_reward_fee",
+			Help:      "Reward fee (in percent) of the staking pool"},
+			[]string{"account_id"},
+		),
+		StakingPoolStakingKeyMismatch: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "staking_pool_staking_key_mismatch",
+			Help:      "Whether the staking key of the pool differs from the public key of the validator"},
+			[]string{"account_id"},
+		),
+		StakingPoolTotalStakedBalance: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "staking_pool_total_staked_balance",
+			Help:      "Total balance staked in the staking pool"},
+			[]string{"account_id"},
+		),
 		SyncingDesc: prometheus.NewGauge(prometheus.GaugeOpts{
 			Namespace: namespace,
 			Name:      "sync_state",
 			Help:      "Sync state",
 		}),
+		ValidatorBlocksProjectedUptime: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "validator_blocks_projected_uptime",
+			Help:      "Projected end of epoch blocks uptime (in percent) of the tracked validator if it produces every remaining block"},
+			[]string{"account_id", "public_key", "epoch_start_height", "tracked"},
+		),
+		ValidatorBlocksTolerableMisses: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "validator_blocks_tolerable_misses",
+			Help:      "Number of blocks the tracked validator can still miss before being kicked out"},
+			[]string{"account_id", "public_key", "epoch_start_height", "tracked"},
+		),
+		ValidatorChunkValidatorOnly: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "validator_chunk_validator_only",
+			Help:      "Whether the validator only endorses chunks, without producing blocks nor chunks"},
+			[]string{"account_id", "public_key", "epoch_start_height", "tracked"},
+		),
+		ValidatorChunksProjectedUptime: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "validator_chunks_projected_uptime",
+			Help:      "Projected end of epoch chunks uptime (in percent) of the tracked validator if it produces every remaining chunk"},
+			[]string{"account_id", "public_key", "epoch_start_height", "tracked"},
+		),
+		ValidatorChunksTolerableMisses: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "validator_chunks_tolerable_misses",
+			Help:      "Number of chunks the tracked validator can still miss before being kicked out"},
+			[]string{"account_id", "public_key", "epoch_start_height", "tracked"},
+		),
 		ValidatorExpectedBlocks: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: namespace,
 			Name:      "validator_blocks_expected",
@@ -102,6 +412,18 @@ func New(namespace string) *Metrics {
 			Help:      "Current amount of validator expected endorsements"},
 			[]string{"account_id", "public_key", "epoch_start_height", "tracked"},
 		),
+		ValidatorInNextEpoch: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "validator_in_next_epoch",
+			Help:      "Whether the tracked validator is part of the next epoch validators"},
+			[]string{"account_id"},
+		),
+		ValidatorNextStakeDelta: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "validator_next_stake_delta",
+			Help:      "Stake of the tracked validator in the next epoch minus its current stake"},
+			[]string{"account_id"},
+		),
 		ValidatorProducedBlocks: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: namespace,
 			Name:      "validator_blocks_produced",
@@ -120,6 +442,42 @@ func New(namespace string) *Metrics {
 			Help:      "Current amount of validator produced endorsements"},
 			[]string{"account_id", "public_key", "epoch_start_height", "tracked"},
 		),
+		ValidatorProposalPending: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "validator_proposal_pending",
+			Help:      "Whether the tracked validator has a pending proposal"},
+			[]string{"account_id"},
+		),
+		ValidatorSeatPriceMargin: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "validator_seat_price_margin",
+			Help:      "Stake of the tracked validator above the seat price (current, next or proposals epoch)"},
+			[]string{"account_id", "epoch"},
+		),
+		ValidatorShardExpectedChunks: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "validator_shard_chunks_expected",
+			Help:      "Current amount of validator expected chunks on the shard"},
+			[]string{"account_id", "public_key", "epoch_start_height", "tracked", "shard_id"},
+		),
+		ValidatorShardExpectedEndorsements: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "validator_shard_endorsements_expected",
+			Help:      "Current amount of validator expected endorsements on the shard"},
+			[]string{"account_id", "public_key", "epoch_start_height", "tracked", "shard_id"},
+		),
+		ValidatorShardProducedChunks: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "validator_shard_chunks_produced",
+			Help:      "Current amount of validator produced chunks on the shard"},
+			[]string{"account_id", "public_key", "epoch_start_height", "tracked", "shard_id"},
+		),
+		ValidatorShardProducedEndorsements: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "validator_shard_endorsements_produced",
+			Help:      "Current amount of validator produced endorsements on the shard"},
+			[]string{"account_id", "public_key", "epoch_start_height", "tracked", "shard_id"},
+		),
 		ValidatorSlashed: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: namespace,
 			Name:      "validator_slashed",
@@ -147,26 +505,82 @@ func New(namespace string) *Metrics {
 	}
 }
 
-func (m *Metrics) Register(reg prometheus.Registerer) {
+// RegisterRuntime registers the process and Go runtime collectors, which are
+// shared by every watched network.
+func RegisterRuntime(reg prometheus.Registerer) {
 	reg.MustRegister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
 	reg.MustRegister(collectors.NewGoCollector())
+}
 
+func (m *Metrics) Register(reg prometheus.Registerer) {
 	reg.MustRegister(m.BlockNumber)
+	reg.MustRegister(m.BlockProducerKickoutThreshold)
+	reg.MustRegister(m.BlocksAuthored)
 	reg.MustRegister(m.ChainID)
+	reg.MustRegister(m.ChunkProducerKickoutThreshold)
+	reg.MustRegister(m.ChunkValidatorOnlyKickoutThreshold)
+	reg.MustRegister(m.CollectionErrors)
 	reg.MustRegister(m.CurrentProposals)
 	reg.MustRegister(m.EpochLength)
+	reg.MustRegister(m.EpochRemainingBlocks)
 	reg.MustRegister(m.EpochStartHeight)
+	reg.MustRegister(m.LastSuccessTimestamp)
+	reg.MustRegister(m.MissedBlocks)
+	reg.MustRegister(m.MissedChunks)
+	reg.MustRegister(m.NextSeatPrice)
 	reg.MustRegister(m.NextValidatorStake)
+	reg.MustRegister(m.NodeValidatorKeyMismatch)
 	reg.MustRegister(m.PrevEpochKickout)
+	reg.MustRegister(m.PrevEpochKickoutExpected)
+	reg.MustRegister(m.PrevEpochKickoutProduced)
+	reg.MustRegister(m.PrevEpochKickoutProtocolVersion)
+	reg.MustRegister(m.PrevEpochKickoutStake)
+	reg.MustRegister(m.PrevEpochKickoutStakeThreshold)
+	reg.MustRegister(m.ProposalsSeatPrice)
 	reg.MustRegister(m.ProtocolVersion)
+	reg.MustRegister(m.ReferenceNodeFork)
+	reg.MustRegister(m.ReferenceNodeHeightLag)
+	reg.MustRegister(m.ReferenceNodeTimeLag)
+	reg.MustRegister(m.RPCEndpointActive)
+	reg.MustRegister(m.RPCEndpointFailures)
+	reg.MustRegister(m.RPCEndpointHealthy)
+	reg.MustRegister(m.RPCEndpointRequests)
+	reg.MustRegister(m.RPCErrors)
+	reg.MustRegister(m.RPCRequestDuration)
+	reg.MustRegister(m.RPCRequests)
 	reg.MustRegister(m.SeatPrice)
+	reg.MustRegister(m.ShardChunksIncluded)
+	reg.MustRegister(m.ShardChunksMissed)
+	reg.MustRegister(m.SkippedBlocks)
+	reg.MustRegister(m.StakingPoolAccounts)
+	reg.MustRegister(m.StakingPoolAPY)
+	reg.MustRegister(m.StakingPoolEpochReward)
+	reg.MustRegister(m.StakingPoolInfo)
+	reg.MustRegister(m.StakingPoolNetAPY)
+	reg.MustRegister(m.StakingPoolPaused)
+	reg.MustRegister(m.StakingPoolRewardFee)
+	reg.MustRegister(m.StakingPoolStakingKeyMismatch)
+	reg.MustRegister(m.StakingPoolTotalStakedBalance)
 	reg.MustRegister(m.SyncingDesc)
+	reg.MustRegister(m.ValidatorBlocksProjectedUptime)
+	reg.MustRegister(m.ValidatorBlocksTolerableMisses)
+	reg.MustRegister(m.ValidatorChunkValidatorOnly)
+	reg.MustRegister(m.ValidatorChunksProjectedUptime)
+	reg.MustRegister(m.ValidatorChunksTolerableMisses)
 	reg.MustRegister(m.ValidatorExpectedBlocks)
 	reg.MustRegister(m.ValidatorExpectedChunks)
 	reg.MustRegister(m.ValidatorExpectedEndorsements)
+	reg.MustRegister(m.ValidatorInNextEpoch)
+	reg.MustRegister(m.ValidatorNextStakeDelta)
 	reg.MustRegister(m.ValidatorProducedBlocks)
 	reg.MustRegister(m.ValidatorProducedChunks)
 	reg.MustRegister(m.ValidatorProducedEndorsements)
+	reg.MustRegister(m.ValidatorProposalPending)
+	reg.MustRegister(m.ValidatorSeatPriceMargin)
+	reg.MustRegister(m.ValidatorShardExpectedChunks)
+	reg.MustRegister(m.ValidatorShardExpectedEndorsements)
+	reg.MustRegister(m.ValidatorShardProducedChunks)
+	reg.MustRegister(m.ValidatorShardProducedEndorsements)
 	reg.MustRegister(m.ValidatorSlashed)
 	reg.MustRegister(m.ValidatorStake)
 	reg.MustRegister(m.ValidatorRank)
@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+)
+
+// ObserveRPCRequest records a request sent by the near client.
+// It is meant to be registered with near.WithRequestObserver.
+func (m *Metrics) ObserveRPCRequest(info near.RequestInfo) {
+	m.RPCRequests.WithLabelValues(info.Method, info.Endpoint).Inc()
+	m.RPCRequestDuration.WithLabelValues(info.Method, info.Endpoint).Observe(info.Duration.Seconds())
+	m.RPCEndpointRequests.WithLabelValues(info.Endpoint).Inc()
+	if info.Failed {
+		m.RPCEndpointFailures.WithLabelValues(info.Endpoint).Inc()
+	}
+
+	if info.Err == nil {
+		return
+	}
+
+	statusCode := ""
+	if info.StatusCode != 0 {
+		statusCode = strconv.Itoa(info.StatusCode)
+	}
+
+	m.RPCErrors.WithLabelValues(info.Method, info.Endpoint, rpcErrorCause(info.Err), statusCode).Inc()
+}
+
+func rpcErrorCause(err error) string {
+	if cause := near.ErrorCauseName(err); cause != "" {
+		return cause
+	}
+
+	var statusErr *near.HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return "HTTP_ERROR"
+	}
+
+	return "TRANSPORT_ERROR"
+}
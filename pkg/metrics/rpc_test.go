@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserveRPCRequest(t *testing.T) {
+	m := New("near_validator_watcher")
+
+	m.ObserveRPCRequest(near.RequestInfo{
+		Endpoint:   "http://node",
+		Method:     "status",
+		Duration:   100 * time.Millisecond,
+		StatusCode: 200,
+	})
+	m.ObserveRPCRequest(near.RequestInfo{
+		Endpoint:   "http://node",
+		Method:     "block",
+		Duration:   100 * time.Millisecond,
+		StatusCode: 200,
+		Err: &near.Error{
+			Name:  near.ErrorNameHandler,
+			Cause: near.ErrorCause{Name: near.ErrorCauseUnknownBlock},
+		},
+	})
+	m.ObserveRPCRequest(near.RequestInfo{
+		Endpoint:   "http://node",
+		Method:     "block",
+		StatusCode: 503,
+		Err:        &near.HTTPStatusError{StatusCode: 503},
+		Failed:     true,
+	})
+	m.ObserveRPCRequest(near.RequestInfo{
+		Endpoint: "http://node",
+		Method:   "validators",
+		Err:      errors.New("connection refused"),
+		Failed:   true,
+	})
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.RPCRequests.WithLabelValues("status", "http://node")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.RPCRequests.WithLabelValues("block", "http://node")))
+	assert.Equal(t, 3, testutil.CollectAndCount(m.RPCRequestDuration))
+	assert.Equal(t, float64(4), testutil.ToFloat64(m.RPCEndpointRequests.WithLabelValues("http://node")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.RPCEndpointFailures.WithLabelValues("http://node")))
+
+	assert.Equal(t, 3, testutil.CollectAndCount(m.RPCErrors))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.RPCErrors.WithLabelValues("block", "http://node", "UNKNOWN_BLOCK", "200")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.RPCErrors.WithLabelValues("block", "http://node", "HTTP_ERROR", "503")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.RPCErrors.WithLabelValues("validators", "http://node", "TRANSPORT_ERROR", "")))
+}
@@ -15,6 +15,7 @@ type BlockResponse struct {
 		NextEpochID           string        `json:"next_epoch_id"`
 		Hash                  string        `json:"hash"`
 		PrevHash              string        `json:"prev_hash"`
+		PrevHeight            int           `json:"prev_height"`
 		PrevStateRoot         string        `json:"prev_state_root"`
 		ChunkReceiptsRoot     string        `json:"chunk_receipts_root"`
 		ChunkHeadersRoot      string        `json:"chunk_headers_root"`
@@ -40,33 +41,47 @@ type BlockResponse struct {
 		Signature             string        `json:"signature"`
 		LatestProtocolVersion int           `json:"latest_protocol_version"`
 	} `json:"header"`
-	Chunks []struct {
-		ChunkHash            string        `json:"chunk_hash"`
-		PrevBlockHash        string        `json:"prev_block_hash"`
-		OutcomeRoot          string        `json:"outcome_root"`
-		PrevStateRoot        string        `json:"prev_state_root"`
-		EncodedMerkleRoot    string        `json:"encoded_merkle_root"`
-		EncodedLength        int           `json:"encoded_length"`
-		HeightCreated        int           `json:"height_created"`
-		HeightIncluded       int           `json:"height_included"`
-		ShardID              int           `json:"shard_id"`
-		GasUsed              int           `json:"gas_used"`
-		GasLimit             int64         `json:"gas_limit"`
-		RentPaid             string        `json:"rent_paid"`
-		ValidatorReward      string        `json:"validator_reward"`
-		BalanceBurnt         string        `json:"balance_burnt"`
-		OutgoingReceiptsRoot string        `json:"outgoing_receipts_root"`
-		TxRoot               string        `json:"tx_root"`
-		ValidatorProposals   []interface{} `json:"validator_proposals"`
-		Signature            string        `json:"signature"`
-	} `json:"chunks"`
+	Chunks []ChunkHeader `json:"chunks"`
+}
+
+type ChunkHeader struct {
+	ChunkHash            string        `json:"chunk_hash"`
+	PrevBlockHash        string        `json:"prev_block_hash"`
+	OutcomeRoot          string        `json:"outcome_root"`
+	PrevStateRoot        string        `json:"prev_state_root"`
+	EncodedMerkleRoot    string        `json:"encoded_merkle_root"`
+	EncodedLength        int           `json:"encoded_length"`
+	HeightCreated        int           `json:"height_created"`
+	HeightIncluded       int           `json:"height_included"`
+	ShardID              int           `json:"shard_id"`
+	GasUsed              int           `json:"gas_used"`
+	GasLimit             int64         `json:"gas_limit"`
+	RentPaid             string        `json:"rent_paid"`
+	ValidatorReward      string        `json:"validator_reward"`
+	BalanceBurnt         string        `json:"balance_burnt"`
+	OutgoingReceiptsRoot string        `json:"outgoing_receipts_root"`
+	TxRoot               string        `json:"tx_root"`
+	ValidatorProposals   []interface{} `json:"validator_proposals"`
+	Signature            string        `json:"signature"`
 }
 
 func (c *Client) Block(ctx context.Context, blockID uint64) (BlockResponse, error) {
-	var req BlockRequest
-	var resp BlockResponse
+	return c.block(ctx, BlockRequest{BlockID: blockID})
+}
 
-	req.BlockID = blockID
+// BlockByHash returns the block of the given hash.
+func (c *Client) BlockByHash(ctx context.Context, hash string) (BlockResponse, error) {
+	return c.block(ctx, BlockRequest{BlockID: hash})
+}
+
+// BlockByFinality returns the latest block with the given finality, either
+// "optimistic" or "final".
+func (c *Client) BlockByFinality(ctx context.Context, finality string) (BlockResponse, error) {
+	return c.block(ctx, BlockRequest{Finality: finality})
+}
+
+func (c *Client) block(ctx context.Context, req BlockRequest) (BlockResponse, error) {
+	var resp BlockResponse
 	err := c.call(ctx, "block", req, &resp)
 	return resp, err
 }
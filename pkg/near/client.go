@@ -4,15 +4,28 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
 )
 
+const defaultRecoveryDelay = 30 * time.Second
+
 type Client struct {
-	httpClient *http.Client
-	Endpoint   string
+	httpClient    *http.Client
+	endpoints     []*endpoint
+	recoveryDelay time.Duration
+	observers     []RequestObserver
+
+	mu     sync.Mutex
+	active *endpoint
 }
 
 type Payload struct {
@@ -26,16 +39,7 @@ type Response struct {
 	JsonRPC string          `json:"jsonrpc"`
 	Id      string          `json:"id"`
 	Result  json.RawMessage `json:"result"`
-	Error   struct {
-		Name    string `json:"name"`
-		Code    int    `json:"code"`
-		Message string `json:"message"`
-		Data    string `json:"data"`
-		Cause   struct {
-			Info interface{} `json:"info"`
-			Name string      `json:"name"`
-		} `json:"cause"`
-	} `json:"error"`
+	Error   *Error          `json:"error"`
 }
 
 type Option func(*Client)
@@ -46,10 +50,26 @@ func WithHTTPClient(httpClient *http.Client) Option {
 	}
 }
 
-func NewClient(endpoint string, options ...Option) *Client {
+// WithRecoveryDelay sets how long a failing endpoint is put aside before
+// being tried again.
+func WithRecoveryDelay(delay time.Duration) Option {
+	return func(c *Client) {
+		c.recoveryDelay = delay
+	}
+}
+
+// NewClient creates a client for the given endpoints. Endpoints are listed by
+// order of preference: the first healthy one is used for every request and the
+// next ones are only used when the preferred ones are failing.
+func NewClient(endpoints []string, options ...Option) *Client {
 	client := &Client{
-		Endpoint:   endpoint,
-		httpClient: &http.Client{},
+		httpClient:    &http.Client{},
+		recoveryDelay: defaultRecoveryDelay,
+	}
+
+	names := EndpointNames(endpoints)
+	for i, rawURL := range endpoints {
+		client.endpoints = append(client.endpoints, &endpoint{url: rawURL, name: names[i]})
 	}
 
 	for _, option := range options {
@@ -59,6 +79,41 @@ func NewClient(endpoint string, options ...Option) *Client {
 	return client
 }
 
+// Endpoints returns the health status of every endpoint, by order of preference.
+func (c *Client) Endpoints() []EndpointStatus {
+	c.mu.Lock()
+	active := c.active
+	c.mu.Unlock()
+
+	statuses := make([]EndpointStatus, 0, len(c.endpoints))
+	for _, e := range c.endpoints {
+		status := e.status()
+		status.Active = e == active
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// candidates returns the endpoints to try, by order of preference. Endpoints
+// that recently failed are moved to the end of the list.
+func (c *Client) candidates() []*endpoint {
+	var (
+		now      = time.Now()
+		eligible = make([]*endpoint, 0, len(c.endpoints))
+		failing  = make([]*endpoint, 0)
+	)
+
+	for _, e := range c.endpoints {
+		if e.isEligible(now, c.recoveryDelay) {
+			eligible = append(eligible, e)
+		} else {
+			failing = append(failing, e)
+		}
+	}
+
+	return append(eligible, failing...)
+}
+
 func (c *Client) Request(ctx context.Context, method string, params interface{}) (*Response, error) {
 	payload, err := json.Marshal(map[string]string{
 		"query": method,
@@ -81,31 +136,108 @@ func (c *Client) Request(ctx context.Context, method string, params interface{})
 		}
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", c.Endpoint, bytes.NewBuffer(payload))
-	req.Header.Set("Content-Type", "application/json")
+	if len(c.endpoints) == 0 {
+		return nil, fmt.Errorf("no rpc endpoint configured")
+	}
+
+	var (
+		rpcResp *Response
+		errs    []error
+	)
+	for i, e := range c.candidates() {
+		if i > 0 {
+			logrus.WithError(err).Warnf("rpc endpoint failing, trying %s", e.name)
+		}
+
+		var (
+			start      = time.Now()
+			statusCode int
+			resp       *Response
+		)
+		resp, statusCode, err = c.requestEndpoint(ctx, e, payload)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		failed := shouldFailover(err, resp)
+		info := RequestInfo{
+			Endpoint:   e.name,
+			Method:     method,
+			Duration:   time.Since(start),
+			StatusCode: statusCode,
+			Err:        err,
+			Failed:     failed,
+		}
+		if err == nil && resp.Error != nil {
+			info.Err = resp.Error
+		}
+		c.observe(info)
+
+		if !failed {
+			e.markSuccess()
+			c.setActive(e)
+			return resp, err
+		}
+
+		if err == nil {
+			err = resp.Error
+			if rpcResp == nil {
+				rpcResp = resp
+			}
+		}
+		e.markFailure(err)
+		errs = append(errs, err)
+	}
+
+	// Every endpoint failed: hand over the first jsonrpc error if there is one
+	// so that callers can inspect it, otherwise every transport error.
+	if rpcResp != nil {
+		return rpcResp, nil
+	}
+	return nil, errors.Join(errs...)
+}
+
+func (c *Client) setActive(e *endpoint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.active = e
+}
+
+func (c *Client) requestEndpoint(ctx context.Context, e *endpoint, payload []byte) (*Response, int, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", e.url, bytes.NewBuffer(payload))
 	if err != nil {
-		return nil, err
+		return nil, 0, fmt.Errorf("invalid rpc endpoint %s", e.name)
 	}
+	req.Header.Set("Content-Type", "application/json")
 
 	r, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		// Transport errors embed the endpoint URL, which may hold credentials
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = e.name
+		}
+		return nil, 0, err
 	}
 	defer r.Body.Close()
 
+	if r.StatusCode >= 500 || r.StatusCode == http.StatusTooManyRequests {
+		return nil, r.StatusCode, &HTTPStatusError{StatusCode: r.StatusCode}
+	}
+
 	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		return nil, err
+		return nil, r.StatusCode, err
 	}
 
 	var resp *Response
 	err = json.Unmarshal(body, &resp)
 
 	if err != nil {
-		return nil, err
+		return nil, r.StatusCode, err
 	}
 
-	return resp, nil
+	return resp, r.StatusCode, nil
 }
 
 func (c *Client) call(ctx context.Context, method string, params interface{}, result interface{}) error {
@@ -114,13 +246,8 @@ func (c *Client) call(ctx context.Context, method string, params interface{}, re
 		return err
 	}
 
-	if resp.Error.Name != "" {
-		return fmt.Errorf(
-			"jsonrpc error(%d): %s %s",
-			resp.Error.Code,
-			resp.Error.Name,
-			resp.Error.Message,
-		)
+	if resp.Error != nil {
+		return resp.Error
 	}
 
 	err = json.Unmarshal(resp.Result, &result)
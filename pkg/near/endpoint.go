@@ -0,0 +1,126 @@
+package near
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// EndpointStatus is a snapshot of the health of an RPC endpoint.
+type EndpointStatus struct {
+	// Name is the endpoint as reported in logs and metrics, see EndpointNames
+	Name                string
+	Healthy             bool
+	Active              bool
+	ConsecutiveFailures int
+	TotalRequests       uint64
+	TotalFailures       uint64
+	LastError           error
+	LastFailure         time.Time
+}
+
+// HTTPStatusError is returned when an RPC endpoint responds with an unexpected HTTP status.
+type HTTPStatusError struct {
+	StatusCode int
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("unexpected http status: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+type endpoint struct {
+	url  string
+	name string
+
+	mu                  sync.Mutex
+	consecutiveFailures int
+	totalRequests       uint64
+	totalFailures       uint64
+	lastError           error
+	lastFailure         time.Time
+}
+
+// EndpointNames returns the names under which endpoints are reported in logs
+// and metrics: their scheme and host only, as their path, query or user info
+// may hold credentials. Endpoints sharing a host are told apart by their
+// position in the list.
+func EndpointNames(urls []string) []string {
+	var (
+		names = make([]string, len(urls))
+		count = make(map[string]int, len(urls))
+	)
+	for i, raw := range urls {
+		names[i] = redactURL(raw)
+		count[names[i]]++
+	}
+	for i, name := range names {
+		if count[name] > 1 {
+			names[i] = fmt.Sprintf("%s#%d", name, i+1)
+		}
+	}
+	return names
+}
+
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "invalid-url"
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// isEligible returns true if the endpoint is healthy or if it has been
+// unhealthy long enough to be tried again.
+func (e *endpoint) isEligible(now time.Time, recoveryDelay time.Duration) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.consecutiveFailures == 0 || now.Sub(e.lastFailure) >= recoveryDelay
+}
+
+func (e *endpoint) markSuccess() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.totalRequests++
+	e.consecutiveFailures = 0
+}
+
+func (e *endpoint) markFailure(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.totalRequests++
+	e.totalFailures++
+	e.consecutiveFailures++
+	e.lastError = err
+	e.lastFailure = time.Now()
+}
+
+func (e *endpoint) status() EndpointStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return EndpointStatus{
+		Name:                e.name,
+		Healthy:             e.consecutiveFailures == 0,
+		ConsecutiveFailures: e.consecutiveFailures,
+		TotalRequests:       e.totalRequests,
+		TotalFailures:       e.totalFailures,
+		LastError:           e.lastError,
+		LastFailure:         e.lastFailure,
+	}
+}
+
+// shouldFailover returns true if the error is caused by the endpoint itself
+// and the request is worth trying against another endpoint.
+func shouldFailover(err error, resp *Response) bool {
+	if err != nil {
+		var statusErr *HTTPStatusError
+		if errors.As(err, &statusErr) {
+			return statusErr.StatusCode >= 500 || statusErr.StatusCode == http.StatusTooManyRequests
+		}
+		// Transport errors (connection refused, timeouts, invalid body, ...)
+		return true
+	}
+	return resp != nil && resp.Error != nil && IsServerError(resp.Error)
+}
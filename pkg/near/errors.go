@@ -0,0 +1,114 @@
+package near
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error names returned by the NEAR JSON-RPC API.
+const (
+	ErrorNameHandler           = "HANDLER_ERROR"
+	ErrorNameInternal          = "INTERNAL_ERROR"
+	ErrorNameRequestValidation = "REQUEST_VALIDATION_ERROR"
+)
+
+// Error cause names returned by the NEAR JSON-RPC API.
+const (
+	ErrorCauseNoSyncedBlocks = "NO_SYNCED_BLOCKS"
+	ErrorCauseNotSyncedYet   = "NOT_SYNCED_YET"
+	ErrorCauseTimeout        = "TIMEOUT_ERROR"
+	ErrorCauseUnknownBlock   = "UNKNOWN_BLOCK"
+	ErrorCauseUnknownEpoch   = "UNKNOWN_EPOCH"
+)
+
+// Sentinel errors to be used with errors.Is against an *Error.
+var (
+	ErrHandler           = errors.New("jsonrpc handler error")
+	ErrInternal          = errors.New("jsonrpc internal error")
+	ErrRequestValidation = errors.New("jsonrpc request validation error")
+	ErrNoSyncedBlocks    = errors.New("jsonrpc no synced blocks")
+	ErrNotSyncedYet      = errors.New("jsonrpc node not synced yet")
+	ErrTimeout           = errors.New("jsonrpc timeout")
+	ErrUnknownBlock      = errors.New("jsonrpc unknown block")
+	ErrUnknownEpoch      = errors.New("jsonrpc unknown epoch")
+)
+
+var errorNames = map[error]string{
+	ErrHandler:           ErrorNameHandler,
+	ErrInternal:          ErrorNameInternal,
+	ErrRequestValidation: ErrorNameRequestValidation,
+}
+
+var errorCauses = map[error]string{
+	ErrNoSyncedBlocks: ErrorCauseNoSyncedBlocks,
+	ErrNotSyncedYet:   ErrorCauseNotSyncedYet,
+	ErrTimeout:        ErrorCauseTimeout,
+	ErrUnknownBlock:   ErrorCauseUnknownBlock,
+	ErrUnknownEpoch:   ErrorCauseUnknownEpoch,
+}
+
+// Error is an error returned by the NEAR JSON-RPC API.
+type Error struct {
+	Name    string      `json:"name"`
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data"`
+	Cause   ErrorCause  `json:"cause"`
+}
+
+// ErrorCause details the reason of an Error.
+type ErrorCause struct {
+	Name string      `json:"name"`
+	Info interface{} `json:"info"`
+}
+
+func (e *Error) Error() string {
+	if e.Cause.Name != "" {
+		return fmt.Sprintf("jsonrpc error(%d): %s %s: %s", e.Code, e.Name, e.Cause.Name, e.Message)
+	}
+	return fmt.Sprintf("jsonrpc error(%d): %s %s", e.Code, e.Name, e.Message)
+}
+
+// Is makes the sentinel errors of this package match an *Error by name or by cause.
+func (e *Error) Is(target error) bool {
+	if name, ok := errorNames[target]; ok {
+		return e.Name == name
+	}
+	if cause, ok := errorCauses[target]; ok {
+		return e.Cause.Name == cause
+	}
+	return false
+}
+
+// IsRetryable returns true if the same request may succeed when sent again.
+// Transport errors are considered retryable while request errors (unknown
+// block, invalid params, ...) are not.
+func IsRetryable(err error) bool {
+	var rpcErr *Error
+	if !errors.As(err, &rpcErr) {
+		return true
+	}
+	return IsServerError(err) ||
+		errors.Is(err, ErrTimeout) ||
+		errors.Is(err, ErrNoSyncedBlocks) ||
+		errors.Is(err, ErrNotSyncedYet)
+}
+
+// IsServerError returns true if the error is caused by the RPC node itself,
+// meaning that the request is worth sending to another node.
+func IsServerError(err error) bool {
+	return errors.Is(err, ErrInternal)
+}
+
+// ErrorCauseName returns the cause of a JSON-RPC error, falling back on the
+// error name if there is no cause. Empty string is returned for other errors.
+func ErrorCauseName(err error) string {
+	var rpcErr *Error
+	if !errors.As(err, &rpcErr) {
+		return ""
+	}
+	if rpcErr.Cause.Name != "" {
+		return rpcErr.Cause.Name
+	}
+	return rpcErr.Name
+}
@@ -0,0 +1,105 @@
+package near
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/near/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestError(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		resp   = testutils.ExpectedResponse{}
+		server = testutils.NewServer(&resp)
+		client = NewClient([]string{server.URL})
+	)
+
+	defer server.Close()
+
+	t.Run("Unknown block", func(t *testing.T) {
+		resp.ExpectResponse(200, `
+			{
+				"jsonrpc": "2.0",
+				"id": "dontcare",
+				"error": {
+					"name": "HANDLER_ERROR",
+					"cause": {
+						"info": {},
+						"name": "UNKNOWN_BLOCK"
+					},
+					"code": -32000,
+					"message": "Server error",
+					"data": "DB Not Found Error: BLOCK HEIGHT: 1"
+				}
+			}`)
+
+		_, err := client.Block(ctx, 1)
+		require.Error(t, err)
+
+		var rpcErr *Error
+		require.ErrorAs(t, err, &rpcErr)
+		assert.Equal(t, "HANDLER_ERROR", rpcErr.Name)
+		assert.Equal(t, -32000, rpcErr.Code)
+		assert.Equal(t, "UNKNOWN_BLOCK", rpcErr.Cause.Name)
+		assert.Equal(t, map[string]interface{}{}, rpcErr.Cause.Info)
+
+		assert.ErrorIs(t, err, ErrHandler)
+		assert.ErrorIs(t, err, ErrUnknownBlock)
+		assert.NotErrorIs(t, err, ErrUnknownEpoch)
+		assert.NotErrorIs(t, err, ErrInternal)
+		assert.False(t, IsRetryable(err))
+		assert.Equal(t, "UNKNOWN_BLOCK", ErrorCauseName(fmt.Errorf("wrapped: %w", err)))
+	})
+
+	t.Run("Request validation", func(t *testing.T) {
+		resp.ExpectResponse(200, `
+			{
+				"jsonrpc": "2.0",
+				"id": "dontcare",
+				"error": {
+					"name": "REQUEST_VALIDATION_ERROR",
+					"cause": {
+						"info": {"error_message": "unknown field"},
+						"name": "PARSE_ERROR"
+					},
+					"code": -32700,
+					"message": "Parse error"
+				}
+			}`)
+
+		_, err := client.Status(ctx)
+		assert.ErrorIs(t, err, ErrRequestValidation)
+		assert.False(t, IsRetryable(err))
+		assert.Equal(t, "PARSE_ERROR", ErrorCauseName(err))
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		resp.ExpectResponse(200, `
+			{
+				"jsonrpc": "2.0",
+				"id": "dontcare",
+				"error": {
+					"name": "HANDLER_ERROR",
+					"cause": {"name": "TIMEOUT_ERROR"},
+					"code": -32000,
+					"message": "Server error"
+				}
+			}`)
+
+		_, err := client.Status(ctx)
+		assert.ErrorIs(t, err, ErrTimeout)
+		assert.True(t, IsRetryable(err))
+		assert.False(t, IsServerError(err))
+	})
+
+	t.Run("Transport error", func(t *testing.T) {
+		err := errors.New("connection refused")
+		assert.True(t, IsRetryable(err))
+		assert.Equal(t, "", ErrorCauseName(err))
+	})
+}
@@ -0,0 +1,263 @@
+package near
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultFollowerConcurrency  = 4
+	defaultFollowerPollInterval = time.Second
+	defaultFollowerBatchSize    = 100
+)
+
+// ErrChainMismatch is returned by a Follower when a block doesn't follow the
+// last block of its cursor, meaning the cursor belongs to another chain or
+// blocks in between are no longer available.
+var ErrChainMismatch = errors.New("block doesn't follow the cursor")
+
+// Cursor is the position of a block stream: the last height consumed, skipped
+// or not, and the hash of the last block consumed. It can be persisted to
+// resume the stream where it stopped.
+type Cursor struct {
+	Height uint64 `json:"height"`
+	Hash   string `json:"hash,omitempty"`
+}
+
+// StreamBlock is a height of the chain, delivered in order.
+type StreamBlock struct {
+	Height uint64
+	// Skipped is true when no block was produced at this height, Block is
+	// then empty
+	Skipped bool
+	Block   BlockResponse
+	// Cursor is the position of the stream once this height is consumed, it
+	// is only set by a Follower
+	Cursor Cursor
+}
+
+// FetchBlocks fetches the heights from..to in order with at most concurrency
+// requests in flight. Heights without block are returned as skipped. On error,
+// the heights fetched before the failing one are returned with the error.
+func FetchBlocks(ctx context.Context, client *Client, from uint64, to uint64, concurrency int) ([]StreamBlock, error) {
+	if to < from {
+		return nil, nil
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		n      = int(to - from + 1)
+		blocks = make([]StreamBlock, n)
+		errs   = make([]error, n)
+		sem    = make(chan struct{}, concurrency)
+		wg     sync.WaitGroup
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			height := from + uint64(i)
+			blocks[i].Height = height
+			blocks[i].Block, errs[i] = client.Block(ctx, height)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if errors.Is(err, ErrUnknownBlock) {
+			blocks[i].Skipped = true
+			continue
+		}
+		if err != nil {
+			return blocks[:i], fmt.Errorf("failed to get block %d: %w", blocks[i].Height, err)
+		}
+	}
+	return blocks, nil
+}
+
+// Follower streams every height of the chain up to the final head, so that
+// delivered blocks are never reorganized and skipped heights are known for
+// sure.
+type Follower struct {
+	client       *Client
+	concurrency  int
+	pollInterval time.Duration
+	batchSize    uint64
+	maxLag       uint64
+	cursor       Cursor
+}
+
+type FollowerOption func(*Follower)
+
+// WithConcurrency sets how many blocks are fetched at once.
+func WithConcurrency(concurrency int) FollowerOption {
+	return func(f *Follower) {
+		f.concurrency = concurrency
+	}
+}
+
+// WithPollInterval sets how often the final head is polled once caught up.
+func WithPollInterval(interval time.Duration) FollowerOption {
+	return func(f *Follower) {
+		f.pollInterval = interval
+	}
+}
+
+// WithBatchSize sets the maximum number of heights fetched by a single poll.
+func WithBatchSize(size uint64) FollowerOption {
+	return func(f *Follower) {
+		f.batchSize = size
+	}
+}
+
+// WithMaxLag restarts the stream at the final head when the cursor is more
+// than maxLag heights behind it, the heights in between being dropped.
+func WithMaxLag(maxLag uint64) FollowerOption {
+	return func(f *Follower) {
+		f.maxLag = maxLag
+	}
+}
+
+// WithCursor resumes the stream after the cursor. Without cursor, the stream
+// starts at the final head.
+func WithCursor(cursor Cursor) FollowerOption {
+	return func(f *Follower) {
+		f.cursor = cursor
+	}
+}
+
+func NewFollower(client *Client, options ...FollowerOption) *Follower {
+	follower := &Follower{
+		client:       client,
+		concurrency:  defaultFollowerConcurrency,
+		pollInterval: defaultFollowerPollInterval,
+		batchSize:    defaultFollowerBatchSize,
+	}
+
+	for _, option := range options {
+		option(follower)
+	}
+
+	return follower
+}
+
+// Cursor returns the position of the follower, it must not be called while
+// Run is running.
+func (f *Follower) Cursor() Cursor {
+	return f.cursor
+}
+
+// Poll returns the heights following the cursor up to the final head, at
+// most the batch size, and moves the cursor after them.
+//
+// Skipped heights are only returned once the next block confirms them, its
+// previous height being the block before them, so that an unknown block
+// reported by a lagging node isn't taken for a skipped height.
+func (f *Follower) Poll(ctx context.Context) ([]StreamBlock, error) {
+	head, err := f.client.BlockByFinality(ctx, "final")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get final block: %w", err)
+	}
+
+	headHeight := uint64(head.Header.Height)
+	if f.cursor.Height == 0 && headHeight > 0 {
+		f.cursor = Cursor{Height: headHeight - 1}
+	}
+	if headHeight <= f.cursor.Height {
+		return nil, nil
+	}
+	if f.maxLag > 0 && headHeight-f.cursor.Height > f.maxLag {
+		logrus.Warnf("skipping %d heights to catch up with final block %d", headHeight-f.cursor.Height, headHeight)
+		f.cursor = Cursor{Height: headHeight - 1}
+	}
+
+	to := headHeight
+	if to-f.cursor.Height > f.batchSize {
+		to = f.cursor.Height + f.batchSize
+	}
+
+	fetched, fetchErr := FetchBlocks(ctx, f.client, f.cursor.Height+1, to, f.concurrency)
+
+	var (
+		blocks  = make([]StreamBlock, 0, len(fetched))
+		skipped = make([]StreamBlock, 0)
+	)
+	for _, block := range fetched {
+		if block.Skipped {
+			skipped = append(skipped, block)
+			continue
+		}
+
+		// Without hash, the cursor height may not be a block
+		header := block.Block.Header
+		if f.cursor.Hash != "" {
+			prevHeight := uint64(header.PrevHeight)
+			if header.PrevHeight != 0 && prevHeight > f.cursor.Height {
+				return blocks, fmt.Errorf("block %d follows block %d which was reported unknown", block.Height, prevHeight)
+			}
+			if header.PrevHash != f.cursor.Hash {
+				return blocks, fmt.Errorf("%w: block %d has previous hash %s instead of %s", ErrChainMismatch, block.Height, header.PrevHash, f.cursor.Hash)
+			}
+		}
+
+		for _, s := range skipped {
+			s.Cursor = Cursor{Height: s.Height, Hash: f.cursor.Hash}
+			blocks = append(blocks, s)
+		}
+		skipped = skipped[:0]
+
+		f.cursor = Cursor{Height: block.Height, Hash: header.Hash}
+		block.Cursor = f.cursor
+		blocks = append(blocks, block)
+	}
+
+	return blocks, fetchErr
+}
+
+// Run delivers every height on out until the context is done. Errors are
+// retried at the next poll, except ErrChainMismatch which is returned. The
+// channel isn't closed.
+func (f *Follower) Run(ctx context.Context, out chan<- StreamBlock) error {
+	for {
+		blocks, err := f.Poll(ctx)
+		for _, block := range blocks {
+			select {
+			case out <- block:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+
+		if errors.Is(err, ErrChainMismatch) {
+			return err
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			logrus.WithError(err).Warn("failed to follow blocks, retrying...")
+		}
+
+		// Keep going without waiting while catching up
+		if err == nil && uint64(len(blocks)) == f.batchSize {
+			continue
+		}
+
+		select {
+		case <-time.After(f.pollInterval):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package near
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chain serves the block method from blocks of a chain by height, hidden
+// blocks being reported unknown as by a lagging node.
+type chain struct {
+	mu     sync.Mutex
+	head   uint64
+	hashes map[uint64]string
+	prev   map[uint64]uint64
+	hidden map[uint64]bool
+}
+
+func (c *chain) produce(height uint64, prevHeight uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hashes[height] = fmt.Sprintf("hash%d", height)
+	c.prev[height] = prevHeight
+	c.head = height
+}
+
+func (c *chain) hide(height uint64, hidden bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hidden[height] = hidden
+}
+
+func (c *chain) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var payload struct {
+		Params BlockRequest `json:"params"`
+	}
+	_ = json.NewDecoder(req.Body).Decode(&payload)
+
+	height := c.head
+	if payload.Params.Finality == "" {
+		height = uint64(payload.Params.BlockID.(float64))
+	}
+	hash, ok := c.hashes[height]
+	if !ok || c.hidden[height] {
+		_, _ = res.Write([]byte(`{"jsonrpc": "2.0", "id": "dontcare", "error": {"name": "HANDLER_ERROR", "code": -32000, "cause": {"name": "UNKNOWN_BLOCK"}}}`))
+		return
+	}
+	_, _ = fmt.Fprintf(res, `{"jsonrpc": "2.0", "id": "dontcare", "result": {"header": {"height": %d, "hash": %q, "prev_height": %d, "prev_hash": "hash%d"}}}`, height, hash, c.prev[height], c.prev[height])
+}
+
+func TestFollower(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		chain  = &chain{hashes: map[uint64]string{}, prev: map[uint64]uint64{}, hidden: map[uint64]bool{}}
+		server = httptest.NewServer(chain)
+		client = NewClient([]string{server.URL})
+	)
+	defer server.Close()
+
+	chain.produce(100, 99)
+
+	t.Run("Poll", func(t *testing.T) {
+		follower := NewFollower(client, WithConcurrency(2))
+
+		blocks, err := follower.Poll(ctx)
+		require.NoError(t, err)
+		require.Len(t, blocks, 1)
+		assert.Equal(t, Cursor{Height: 100, Hash: "hash100"}, blocks[0].Cursor)
+
+		// Height 103 is skipped
+		chain.produce(101, 100)
+		chain.produce(102, 101)
+		chain.produce(104, 102)
+		chain.produce(105, 104)
+
+		blocks, err = follower.Poll(ctx)
+		require.NoError(t, err)
+		require.Len(t, blocks, 5)
+		for i, block := range blocks {
+			assert.Equal(t, uint64(101+i), block.Height)
+		}
+		assert.True(t, blocks[2].Skipped)
+		assert.Equal(t, Cursor{Height: 103, Hash: "hash102"}, blocks[2].Cursor)
+		assert.Equal(t, Cursor{Height: 105, Hash: "hash105"}, follower.Cursor())
+
+		blocks, err = follower.Poll(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, blocks)
+	})
+
+	t.Run("Resume from cursor", func(t *testing.T) {
+		follower := NewFollower(client, WithCursor(Cursor{Height: 102, Hash: "hash102"}), WithBatchSize(2))
+
+		out := make(chan StreamBlock)
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go func() {
+			_ = follower.Run(ctx, out)
+		}()
+
+		for _, height := range []uint64{103, 104, 105} {
+			select {
+			case block := <-out:
+				assert.Equal(t, height, block.Height)
+			case <-time.After(5 * time.Second):
+				t.Fatal("timeout waiting for block")
+			}
+		}
+	})
+
+	t.Run("Unknown block", func(t *testing.T) {
+		follower := NewFollower(client, WithCursor(Cursor{Height: 102, Hash: "hash102"}))
+
+		// A lagging node reports block 104 unknown, 103 is only skipped if the
+		// next block confirms it
+		chain.hide(104, true)
+		blocks, err := follower.Poll(ctx)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrChainMismatch)
+		assert.Empty(t, blocks)
+		assert.Equal(t, Cursor{Height: 102, Hash: "hash102"}, follower.Cursor())
+
+		chain.hide(104, false)
+		blocks, err = follower.Poll(ctx)
+		require.NoError(t, err)
+		require.Len(t, blocks, 3)
+		assert.True(t, blocks[0].Skipped)
+		assert.False(t, blocks[1].Skipped)
+		assert.Equal(t, Cursor{Height: 105, Hash: "hash105"}, follower.Cursor())
+	})
+
+	t.Run("Chain mismatch", func(t *testing.T) {
+		follower := NewFollower(client, WithCursor(Cursor{Height: 101, Hash: "other101"}))
+
+		blocks, err := follower.Poll(ctx)
+		assert.ErrorIs(t, err, ErrChainMismatch)
+		assert.Empty(t, blocks)
+		assert.Equal(t, Cursor{Height: 101, Hash: "other101"}, follower.Cursor())
+	})
+}
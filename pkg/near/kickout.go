@@ -0,0 +1,104 @@
+package near
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+type KickoutReasonType string
+
+const (
+	KickoutReasonNotEnoughBlocks            KickoutReasonType = "NotEnoughBlocks"
+	KickoutReasonNotEnoughChunks            KickoutReasonType = "NotEnoughChunks"
+	KickoutReasonNotEnoughChunkEndorsements KickoutReasonType = "NotEnoughChunkEndorsements"
+	KickoutReasonNotEnoughStake             KickoutReasonType = "NotEnoughStake"
+	KickoutReasonSlashed                    KickoutReasonType = "Slashed"
+	KickoutReasonUnstaked                   KickoutReasonType = "Unstaked"
+	KickoutReasonDidNotGetASeat             KickoutReasonType = "DidNotGetASeat"
+	KickoutReasonProtocolVersionTooOld      KickoutReasonType = "ProtocolVersionTooOld"
+)
+
+type ValidatorKickout struct {
+	AccountId string        `json:"account_id"`
+	Reason    KickoutReason `json:"reason"`
+}
+
+// KickoutReason explains why a validator was kicked out. Only the details of
+// its Type are set:
+//   - Produced and Expected for NotEnoughBlocks, NotEnoughChunks and NotEnoughChunkEndorsements
+//   - Stake and Threshold for NotEnoughStake
+//   - Version and NetworkVersion for ProtocolVersionTooOld
+type KickoutReason struct {
+	Type KickoutReasonType
+
+	Produced int64
+	Expected int64
+
+	Stake     decimal.Decimal
+	Threshold decimal.Decimal
+
+	Version        int
+	NetworkVersion int
+}
+
+// UnmarshalJSON decodes reasons serialized either as a string for the reasons
+// without details (eg. "Slashed"), or as an object with a single key holding
+// the details (eg. {"NotEnoughBlocks": {"produced": 0, "expected": 16}}).
+// Unknown reasons only get their Type set.
+func (r *KickoutReason) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*r = KickoutReason{Type: KickoutReasonType(name)}
+		return nil
+	}
+
+	var variants map[string]json.RawMessage
+	if err := json.Unmarshal(data, &variants); err != nil {
+		return fmt.Errorf("invalid kickout reason: %w", err)
+	}
+	if len(variants) != 1 {
+		return fmt.Errorf("invalid kickout reason: expected a single reason, got %d", len(variants))
+	}
+
+	for name, raw := range variants {
+		*r = KickoutReason{Type: KickoutReasonType(name)}
+
+		var details struct {
+			Produced       int64           `json:"produced"`
+			Expected       int64           `json:"expected"`
+			Stake          decimal.Decimal `json:"stake_u128"`
+			Threshold      decimal.Decimal `json:"threshold_u128"`
+			Version        int             `json:"version"`
+			NetworkVersion int             `json:"network_version"`
+		}
+		if err := json.Unmarshal(raw, &details); err != nil {
+			return fmt.Errorf("invalid %s kickout reason: %w", name, err)
+		}
+
+		switch r.Type {
+		case KickoutReasonNotEnoughBlocks, KickoutReasonNotEnoughChunks, KickoutReasonNotEnoughChunkEndorsements:
+			r.Produced, r.Expected = details.Produced, details.Expected
+		case KickoutReasonNotEnoughStake:
+			r.Stake, r.Threshold = details.Stake, details.Threshold
+		case KickoutReasonProtocolVersionTooOld:
+			r.Version, r.NetworkVersion = details.Version, details.NetworkVersion
+		}
+	}
+
+	return nil
+}
+
+func (r KickoutReason) String() string {
+	switch r.Type {
+	case KickoutReasonNotEnoughBlocks, KickoutReasonNotEnoughChunks, KickoutReasonNotEnoughChunkEndorsements:
+		return fmt.Sprintf("%s (produced %d, expected %d)", r.Type, r.Produced, r.Expected)
+	case KickoutReasonNotEnoughStake:
+		return fmt.Sprintf("%s (stake %s, threshold %s)", r.Type, r.Stake, r.Threshold)
+	case KickoutReasonProtocolVersionTooOld:
+		return fmt.Sprintf("%s (version %d, network version %d)", r.Type, r.Version, r.NetworkVersion)
+	default:
+		return string(r.Type)
+	}
+}
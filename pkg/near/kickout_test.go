@@ -0,0 +1,61 @@
+package near
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKickoutReason(t *testing.T) {
+	for _, tc := range []struct {
+		json     string
+		expected KickoutReason
+		str      string
+	}{
+		{
+			json:     `"Slashed"`,
+			expected: KickoutReason{Type: KickoutReasonSlashed},
+			str:      "Slashed",
+		},
+		{
+			json:     `{"NotEnoughBlocks": {"expected": 16, "produced": 2}}`,
+			expected: KickoutReason{Type: KickoutReasonNotEnoughBlocks, Produced: 2, Expected: 16},
+			str:      "NotEnoughBlocks (produced 2, expected 16)",
+		},
+		{
+			json:     `{"NotEnoughChunkEndorsements": {"expected": 300, "produced": 10}}`,
+			expected: KickoutReason{Type: KickoutReasonNotEnoughChunkEndorsements, Produced: 10, Expected: 300},
+			str:      "NotEnoughChunkEndorsements (produced 10, expected 300)",
+		},
+		{
+			json: `{"NotEnoughStake": {"stake_u128": "1000", "threshold_u128": "2000"}}`,
+			expected: KickoutReason{
+				Type:      KickoutReasonNotEnoughStake,
+				Stake:     decimal.NewFromInt(1000),
+				Threshold: decimal.NewFromInt(2000),
+			},
+			str: "NotEnoughStake (stake 1000, threshold 2000)",
+		},
+		{
+			json:     `{"ProtocolVersionTooOld": {"version": 64, "network_version": 66}}`,
+			expected: KickoutReason{Type: KickoutReasonProtocolVersionTooOld, Version: 64, NetworkVersion: 66},
+			str:      "ProtocolVersionTooOld (version 64, network version 66)",
+		},
+		{
+			json:     `{"SomethingNew": {"foo": "bar"}}`,
+			expected: KickoutReason{Type: "SomethingNew"},
+			str:      "SomethingNew",
+		},
+	} {
+		var reason KickoutReason
+		require.NoError(t, json.Unmarshal([]byte(tc.json), &reason), tc.json)
+		assert.Equal(t, tc.expected.Type, reason.Type)
+		assert.Equal(t, tc.str, reason.String())
+	}
+
+	var reason KickoutReason
+	assert.Error(t, json.Unmarshal([]byte(`{"Slashed": null, "Unstaked": null}`), &reason))
+}
@@ -0,0 +1,34 @@
+package near
+
+import "time"
+
+// RequestInfo describes a request sent to an RPC endpoint.
+type RequestInfo struct {
+	Endpoint string
+	Method   string
+	Duration time.Duration
+	// StatusCode is the HTTP status code, 0 if no response was received.
+	StatusCode int
+	// Err is either a transport error, an *HTTPStatusError or an *Error.
+	Err error
+	// Failed is true when the endpoint is considered failing for the request,
+	// which is then retried on the next endpoint.
+	Failed bool
+}
+
+// RequestObserver is called after every request sent to an RPC endpoint,
+// including the ones that are retried on another endpoint.
+type RequestObserver func(RequestInfo)
+
+// WithRequestObserver registers an observer to instrument the client.
+func WithRequestObserver(observer RequestObserver) Option {
+	return func(c *Client) {
+		c.observers = append(c.observers, observer)
+	}
+}
+
+func (c *Client) observe(info RequestInfo) {
+	for _, observer := range c.observers {
+		observer(info)
+	}
+}
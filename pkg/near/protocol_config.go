@@ -6,25 +6,28 @@ import (
 )
 
 type ProtocolConfigResponse struct {
-	ProtocolVersion                 int       `json:"protocol_version"`
-	GenesisTime                     time.Time `json:"genesis_time"`
-	ChainID                         string    `json:"chain_id"`
-	GenesisHeight                   int       `json:"genesis_height"`
-	NumBlockProducerSeats           int       `json:"num_block_producer_seats"`
-	NumBlockProducerSeatsPerShard   []int     `json:"num_block_producer_seats_per_shard"`
-	AvgHiddenValidatorSeatsPerShard []int     `json:"avg_hidden_validator_seats_per_shard"`
-	DynamicResharding               bool      `json:"dynamic_resharding"`
-	ProtocolUpgradeStakeThreshold   []int     `json:"protocol_upgrade_stake_threshold"`
-	EpochLength                     int       `json:"epoch_length"`
-	GasLimit                        int64     `json:"gas_limit"`
-	MinGasPrice                     string    `json:"min_gas_price"`
-	MaxGasPrice                     string    `json:"max_gas_price"`
-	BlockProducerKickoutThreshold   int       `json:"block_producer_kickout_threshold"`
-	ChunkProducerKickoutThreshold   int       `json:"chunk_producer_kickout_threshold"`
-	OnlineMinThreshold              []int     `json:"online_min_threshold"`
-	OnlineMaxThreshold              []int     `json:"online_max_threshold"`
-	GasPriceAdjustmentRate          []int     `json:"gas_price_adjustment_rate"`
-	RuntimeConfig                   struct {
+	ProtocolVersion                    int       `json:"protocol_version"`
+	GenesisTime                        time.Time `json:"genesis_time"`
+	ChainID                            string    `json:"chain_id"`
+	GenesisHeight                      int       `json:"genesis_height"`
+	NumBlockProducerSeats              int       `json:"num_block_producer_seats"`
+	NumBlockProducerSeatsPerShard      []int     `json:"num_block_producer_seats_per_shard"`
+	NumChunkProducerSeats              int       `json:"num_chunk_producer_seats"`
+	NumChunkValidatorSeats             int       `json:"num_chunk_validator_seats"`
+	AvgHiddenValidatorSeatsPerShard    []int     `json:"avg_hidden_validator_seats_per_shard"`
+	DynamicResharding                  bool      `json:"dynamic_resharding"`
+	ProtocolUpgradeStakeThreshold      []int     `json:"protocol_upgrade_stake_threshold"`
+	EpochLength                        int       `json:"epoch_length"`
+	GasLimit                           int64     `json:"gas_limit"`
+	MinGasPrice                        string    `json:"min_gas_price"`
+	MaxGasPrice                        string    `json:"max_gas_price"`
+	BlockProducerKickoutThreshold      int       `json:"block_producer_kickout_threshold"`
+	ChunkProducerKickoutThreshold      int       `json:"chunk_producer_kickout_threshold"`
+	ChunkValidatorOnlyKickoutThreshold int       `json:"chunk_validator_only_kickout_threshold"`
+	OnlineMinThreshold                 []int     `json:"online_min_threshold"`
+	OnlineMaxThreshold                 []int     `json:"online_max_threshold"`
+	GasPriceAdjustmentRate             []int     `json:"gas_price_adjustment_rate"`
+	RuntimeConfig                      struct {
 		StorageAmountPerByte string `json:"storage_amount_per_byte"`
 		TransactionCosts     struct {
 			ActionReceiptCreationConfig struct {
@@ -203,13 +206,28 @@ type ProtocolConfigResponse struct {
 			RegistrarAccountID              string `json:"registrar_account_id"`
 		} `json:"account_creation_config"`
 	} `json:"runtime_config"`
-	TransactionValidityPeriod int    `json:"transaction_validity_period"`
-	ProtocolRewardRate        []int  `json:"protocol_reward_rate"`
-	MaxInflationRate          []int  `json:"max_inflation_rate"`
-	NumBlocksPerYear          int    `json:"num_blocks_per_year"`
-	ProtocolTreasuryAccount   string `json:"protocol_treasury_account"`
-	FishermenThreshold        string `json:"fishermen_threshold"`
-	MinimumStakeDivisor       int    `json:"minimum_stake_divisor"`
+	TransactionValidityPeriod int     `json:"transaction_validity_period"`
+	ProtocolRewardRate        []int   `json:"protocol_reward_rate"`
+	MaxInflationRate          []int   `json:"max_inflation_rate"`
+	NumBlocksPerYear          int     `json:"num_blocks_per_year"`
+	ProtocolTreasuryAccount   string  `json:"protocol_treasury_account"`
+	FishermenThreshold        string  `json:"fishermen_threshold"`
+	MinimumStakeDivisor       int     `json:"minimum_stake_divisor"`
+	MinimumStakeRatio         []int64 `json:"minimum_stake_ratio"`
+}
+
+// NumSeats returns the number of validator seats. With stateless validation,
+// validators are selected as block producers, chunk producers or chunk
+// validators, the seat price being the lowest of the roles thresholds.
+func (c ProtocolConfigResponse) NumSeats() int {
+	seats := c.NumBlockProducerSeats
+	if c.NumChunkProducerSeats > seats {
+		seats = c.NumChunkProducerSeats
+	}
+	if c.NumChunkValidatorSeats > seats {
+		seats = c.NumChunkValidatorSeats
+	}
+	return seats
 }
 
 func (c *Client) ProtocolConfig(ctx context.Context) (ProtocolConfigResponse, error) {
@@ -0,0 +1,121 @@
+package near
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// defaultMinimumStakeRatio is the ratio of the total selected stake a
+// validator must hold to be selected.
+var defaultMinimumStakeRatio = [2]int64{1, 6250}
+
+// FindSeatPrice computes the seat price given the stakes of the validators, as
+// done by nearcore when selecting validators (see validator_selection.rs).
+func FindSeatPrice(stakes []decimal.Decimal, numSeats int, minimumStakeRatio []int64, protocolVersion int) (decimal.Decimal, error) {
+	if len(stakes) == 0 {
+		return decimal.Zero, fmt.Errorf("no stakes to compute seat price from")
+	}
+	if numSeats <= 0 {
+		return decimal.Zero, fmt.Errorf("no seats to compute seat price from")
+	}
+
+	values := make([]*big.Int, 0, len(stakes))
+	for _, stake := range stakes {
+		values = append(values, stake.BigInt())
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Cmp(values[j]) < 0
+	})
+
+	if protocolVersion != 0 && protocolVersion < 49 {
+		return findSeatPriceBefore49(values, numSeats)
+	}
+
+	ratio := defaultMinimumStakeRatio
+	if len(minimumStakeRatio) == 2 && minimumStakeRatio[1] != 0 {
+		ratio = [2]int64{minimumStakeRatio[0], minimumStakeRatio[1]}
+	}
+
+	return findSeatPriceAfter49(values, numSeats, ratio), nil
+}
+
+// Since protocol version 49, validators are selected by decreasing stake as
+// long as their stake is more than the minimum ratio of the total selected
+// stake. When every seat is filled, the seat price is one more than the
+// smallest selected stake, otherwise it is the smallest stake passing the
+// ratio condition: ceil(ratio * total / (1 - ratio)).
+func findSeatPriceAfter49(stakes []*big.Int, numSeats int, minimumStakeRatio [2]int64) decimal.Decimal {
+	var (
+		num      = big.NewInt(minimumStakeRatio[0])
+		den      = big.NewInt(minimumStakeRatio[1])
+		total    = new(big.Int)
+		selected = 0
+	)
+	for i := len(stakes) - 1; i >= 0 && selected < numSeats; i-- {
+		// stake / (total + stake) > num / den
+		withStake := new(big.Int).Add(total, stakes[i])
+		if new(big.Int).Mul(stakes[i], den).Cmp(new(big.Int).Mul(withStake, num)) <= 0 {
+			break
+		}
+		total = withStake
+		selected++
+	}
+
+	if selected == numSeats {
+		return decimal.NewFromBigInt(new(big.Int).Add(stakes[len(stakes)-numSeats], big.NewInt(1)), 0)
+	}
+
+	// num * total / (den - num), rounded up
+	var (
+		numerator   = new(big.Int).Mul(total, num)
+		denominator = new(big.Int).Sub(den, num)
+	)
+	numerator.Add(numerator, denominator)
+	numerator.Sub(numerator, big.NewInt(1))
+	return decimal.NewFromBigInt(numerator.Quo(numerator, denominator), 0)
+}
+
+// Before protocol version 49, the seat price is the highest price for which
+// the validators stakes can fill every seat.
+func findSeatPriceBefore49(stakes []*big.Int, numSeats int) (decimal.Decimal, error) {
+	var (
+		seats = big.NewInt(int64(numSeats))
+		sum   = new(big.Int)
+	)
+	for _, stake := range stakes {
+		sum.Add(sum, stake)
+	}
+	if sum.Cmp(seats) < 0 {
+		return decimal.Zero, fmt.Errorf("stakes are below seats")
+	}
+
+	var (
+		one   = big.NewInt(1)
+		left  = big.NewInt(1)
+		right = new(big.Int).Add(sum, one)
+	)
+	for new(big.Int).Sub(right, left).Cmp(one) > 0 {
+		var (
+			mid        = new(big.Int).Quo(new(big.Int).Add(left, right), big.NewInt(2))
+			currentSum = new(big.Int)
+			found      = false
+		)
+		for _, stake := range stakes {
+			currentSum.Add(currentSum, new(big.Int).Quo(stake, mid))
+			if currentSum.Cmp(seats) >= 0 {
+				found = true
+				break
+			}
+		}
+		if found {
+			left = mid
+		} else {
+			right = mid
+		}
+	}
+
+	return decimal.NewFromBigInt(left, 0), nil
+}
@@ -0,0 +1,75 @@
+package near
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindSeatPrice(t *testing.T) {
+	stakes := []decimal.Decimal{
+		decimal.NewFromInt(1000000),
+		decimal.NewFromInt(1000000),
+		decimal.NewFromInt(100),
+	}
+
+	t.Run("More seats than validators", func(t *testing.T) {
+		seatPrice, err := FindSeatPrice(stakes, 100, []int64{1, 25}, 49)
+		require.NoError(t, err)
+		assert.Equal(t, "83334", seatPrice.String())
+
+		// Default minimum stake ratio
+		seatPrice, err = FindSeatPrice(stakes, 100, nil, 49)
+		require.NoError(t, err)
+		assert.Equal(t, "321", seatPrice.String())
+	})
+
+	t.Run("Fewer seats than validators", func(t *testing.T) {
+		seatPrice, err := FindSeatPrice(stakes, 2, []int64{1, 6250}, 63)
+		require.NoError(t, err)
+		assert.Equal(t, "1000001", seatPrice.String())
+
+		// The smallest stake is below the minimum stake ratio
+		seatPrice, err = FindSeatPrice(stakes, 3, []int64{1, 6250}, 63)
+		require.NoError(t, err)
+		assert.Equal(t, "321", seatPrice.String())
+
+		// Every stake gets a seat
+		seatPrice, err = FindSeatPrice(stakes[:2], 2, []int64{1, 6250}, 63)
+		require.NoError(t, err)
+		assert.Equal(t, "1000001", seatPrice.String())
+	})
+
+	t.Run("No seats", func(t *testing.T) {
+		_, err := FindSeatPrice(stakes, 0, nil, 63)
+		assert.Error(t, err)
+	})
+
+	t.Run("Before protocol version 49", func(t *testing.T) {
+		seatPrice, err := FindSeatPrice([]decimal.Decimal{
+			decimal.NewFromInt(1000000),
+			decimal.NewFromInt(1000000),
+			decimal.NewFromInt(100),
+		}, 2, nil, 48)
+		require.NoError(t, err)
+		assert.Equal(t, "1000000", seatPrice.String())
+
+		seatPrice, err = FindSeatPrice([]decimal.Decimal{
+			decimal.NewFromInt(1000000),
+			decimal.NewFromInt(1000000),
+			decimal.NewFromInt(100),
+		}, 20, nil, 48)
+		require.NoError(t, err)
+		assert.Equal(t, "100000", seatPrice.String())
+
+		_, err = FindSeatPrice([]decimal.Decimal{decimal.NewFromInt(1)}, 2, nil, 48)
+		assert.Error(t, err)
+	})
+
+	t.Run("No stakes", func(t *testing.T) {
+		_, err := FindSeatPrice(nil, 100, nil, 63)
+		assert.Error(t, err)
+	})
+}
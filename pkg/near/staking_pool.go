@@ -0,0 +1,109 @@
+package near
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// RewardFeeFraction is the part of the rewards kept by the staking pool owner.
+type RewardFeeFraction struct {
+	Numerator   uint64 `json:"numerator"`
+	Denominator uint64 `json:"denominator"`
+}
+
+// Percent returns the fee in percent.
+func (f RewardFeeFraction) Percent() float64 {
+	if f.Denominator == 0 {
+		return 0
+	}
+	return 100 * float64(f.Numerator) / float64(f.Denominator)
+}
+
+// StakingPool holds the state of a staking pool contract, as returned by the
+// view methods of the standard staking pool.
+type StakingPool struct {
+	OwnerID            string
+	RewardFeeFraction  RewardFeeFraction
+	TotalStakedBalance decimal.Decimal
+	NumberOfAccounts   uint64
+	StakingPaused      bool
+	StakingKey         string
+}
+
+// ViewFunction calls a view method of a contract and decodes its JSON result.
+func (c *Client) ViewFunction(ctx context.Context, accountID string, methodName string, result interface{}, opts ...QueryOption) error {
+	if len(opts) == 0 {
+		opts = []QueryOption{QueryWithFinality("final")}
+	}
+	opts = append([]QueryOption{QueryWithArgs(nil)}, opts...)
+
+	resp, err := c.CallFunction(ctx, accountID, methodName, opts...)
+	if err != nil {
+		return err
+	}
+	if resp.Error != "" {
+		return fmt.Errorf("failed to call %s on %s: %s", methodName, accountID, resp.Error)
+	}
+
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("failed to decode %s result of %s: %w", methodName, accountID, err)
+	}
+	return nil
+}
+
+// StakingPool calls the view methods of the staking pool contract deployed on
+// the account.
+func (c *Client) StakingPool(ctx context.Context, accountID string, opts ...QueryOption) (StakingPool, error) {
+	var pool StakingPool
+	err := c.viewFunctions(ctx, accountID, append(pool.settingsCalls(), pool.stateCalls()...), opts...)
+	return pool, err
+}
+
+// StakingPoolSettings only calls the view methods returning the settings the
+// owner of the staking pool can change: owner, reward fee and staking key.
+func (c *Client) StakingPoolSettings(ctx context.Context, accountID string, opts ...QueryOption) (StakingPool, error) {
+	var pool StakingPool
+	err := c.viewFunctions(ctx, accountID, pool.settingsCalls(), opts...)
+	return pool, err
+}
+
+// StakingPoolState only calls the view methods returning the state of the
+// staking pool: total staked balance, number of accounts and paused staking.
+func (c *Client) StakingPoolState(ctx context.Context, accountID string, opts ...QueryOption) (StakingPool, error) {
+	var pool StakingPool
+	err := c.viewFunctions(ctx, accountID, pool.stateCalls(), opts...)
+	return pool, err
+}
+
+type viewCall struct {
+	method string
+	result interface{}
+}
+
+func (p *StakingPool) settingsCalls() []viewCall {
+	return []viewCall{
+		{"get_owner_id", &p.OwnerID},
+		{"get_reward_fee_fraction", &p.RewardFeeFraction},
+		{"get_staking_key", &p.StakingKey},
+	}
+}
+
+func (p *StakingPool) stateCalls() []viewCall {
+	return []viewCall{
+		{"get_total_staked_balance", &p.TotalStakedBalance},
+		{"get_number_of_accounts", &p.NumberOfAccounts},
+		{"is_staking_paused", &p.StakingPaused},
+	}
+}
+
+func (c *Client) viewFunctions(ctx context.Context, accountID string, calls []viewCall, opts ...QueryOption) error {
+	for _, call := range calls {
+		if err := c.ViewFunction(ctx, accountID, call.method, call.result, opts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package near
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStakingPool(t *testing.T) {
+	results := map[string]string{
+		"get_owner_id":             `"kiln.near"`,
+		"get_reward_fee_fraction":  `{"numerator": 7, "denominator": 100}`,
+		"get_total_staked_balance": `"23052051536090128427622637823"`,
+		"get_number_of_accounts":   `42`,
+		"is_staking_paused":        `false`,
+		"get_staking_key":          `"ed25519:9NBTDmtpY4ALxD2WRqbNC2FX5ATcGdbk8tLWKBbVJoRX"`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Params QueryRequest `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "final", req.Params.Finality)
+
+		result, ok := results[req.Params.MethodName]
+		if !ok {
+			fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": "dontcare", "result": {"error": "MethodNotFound", "logs": []}}`)
+			return
+		}
+		// Results are returned as arrays of bytes
+		bytes := make([]int, 0, len(result))
+		for _, b := range []byte(result) {
+			bytes = append(bytes, int(b))
+		}
+		raw, _ := json.Marshal(bytes)
+		fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": "dontcare", "result": {"result": %s, "logs": []}}`, raw)
+	}))
+	defer server.Close()
+
+	client := NewClient([]string{server.URL})
+
+	pool, err := client.StakingPool(context.Background(), "kiln.poolv1.near")
+	require.NoError(t, err)
+
+	assert.Equal(t, "kiln.near", pool.OwnerID)
+	assert.Equal(t, float64(7), pool.RewardFeeFraction.Percent())
+	assert.Equal(t, decimal.RequireFromString("23052051536090128427622637823"), pool.TotalStakedBalance)
+	assert.Equal(t, uint64(42), pool.NumberOfAccounts)
+	assert.False(t, pool.StakingPaused)
+	assert.Equal(t, "ed25519:9NBTDmtpY4ALxD2WRqbNC2FX5ATcGdbk8tLWKBbVJoRX", pool.StakingKey)
+
+	settings, err := client.StakingPoolSettings(context.Background(), "kiln.poolv1.near")
+	require.NoError(t, err)
+	assert.Equal(t, StakingPool{
+		OwnerID:           "kiln.near",
+		RewardFeeFraction: RewardFeeFraction{Numerator: 7, Denominator: 100},
+		StakingKey:        "ed25519:9NBTDmtpY4ALxD2WRqbNC2FX5ATcGdbk8tLWKBbVJoRX",
+	}, settings)
+
+	delete(results, "is_staking_paused")
+	_, err = client.StakingPoolSettings(context.Background(), "kiln.poolv1.near")
+	require.NoError(t, err)
+
+	_, err = client.StakingPool(context.Background(), "kiln.poolv1.near")
+	assert.ErrorContains(t, err, "failed to call is_staking_paused on kiln.poolv1.near: MethodNotFound")
+}
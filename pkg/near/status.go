@@ -5,6 +5,7 @@ import "context"
 type StatusResponse struct {
 	ChainID               string `json:"chain_id"`
 	LatestProtocolVersion int    `json:"latest_protocol_version"`
+	NodePublicKey         string `json:"node_public_key"`
 	ProtocolVersion       int    `json:"protocol_version"`
 	RpcAddr               string `json:"rpc_addr"`
 	SyncInfo              struct {
@@ -17,6 +18,9 @@ type StatusResponse struct {
 		LatestBlockTime     string `json:"latest_block_time"`
 		Syncing             bool   `json:"syncing"`
 	} `json:"sync_info"`
+	// Account and key the node validates with, empty if it isn't a validator
+	ValidatorAccountID string `json:"validator_account_id"`
+	ValidatorPublicKey string `json:"validator_public_key"`
 	//Validators []string `json:"validators"`
 	Version struct {
 		Version string `json:"version"`
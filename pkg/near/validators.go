@@ -2,23 +2,14 @@ package near
 
 import (
 	"context"
+	"sort"
 
 	"github.com/shopspring/decimal"
 )
 
 type ValidatorsResponse struct {
-	CurrentValidators []struct {
-		Validator
-		IsSlashed               bool  `json:"is_slashed"`
-		Shards                  []int `json:"shards"`
-		NumProducedBlocks       int64 `json:"num_produced_blocks"`
-		NumExpectedBlocks       int64 `json:"num_expected_blocks"`
-		NumProducedChunks       int64 `json:"num_produced_chunks"`
-		NumExpectedChunks       int64 `json:"num_expected_chunks"`
-		NumProducedEndorsements int64 `json:"num_produced_endorsements"`
-		NumExpectedEndorsements int64 `json:"num_expected_endorsements"`
-	} `json:"current_validators"`
-	NextValidators []struct {
+	CurrentValidators []CurrentEpochValidatorInfo `json:"current_validators"`
+	NextValidators    []struct {
 		Validator
 		Shards []int `json:"shards"`
 	} `json:"next_validators"`
@@ -26,12 +17,119 @@ type ValidatorsResponse struct {
 		Validator
 		StakeStructVersion string `json:"validator_stake_struct_version"`
 	} `json:"current_proposals"`
-	EpochStartHeight int64 `json:"epoch_start_height"`
-	EpochHeight      int64 `json:"epoch_height"`
-	PrevEpochKickOut []struct {
-		AccountId string      `json:"account_id"`
-		Reason    interface{} `json:"reason"`
-	} `json:"prev_epoch_kickout"`
+	EpochStartHeight int64              `json:"epoch_start_height"`
+	EpochHeight      int64              `json:"epoch_height"`
+	PrevEpochKickOut []ValidatorKickout `json:"prev_epoch_kickout"`
+}
+
+type CurrentEpochValidatorInfo struct {
+	Validator
+	IsSlashed               bool  `json:"is_slashed"`
+	Shards                  []int `json:"shards"`
+	ShardsEndorsed          []int `json:"shards_endorsed"`
+	NumProducedBlocks       int64 `json:"num_produced_blocks"`
+	NumExpectedBlocks       int64 `json:"num_expected_blocks"`
+	NumProducedChunks       int64 `json:"num_produced_chunks"`
+	NumExpectedChunks       int64 `json:"num_expected_chunks"`
+	NumProducedEndorsements int64 `json:"num_produced_endorsements"`
+	NumExpectedEndorsements int64 `json:"num_expected_endorsements"`
+	// Per shard stats, in the same order as Shards
+	NumProducedChunksPerShard []int64 `json:"num_produced_chunks_per_shard"`
+	NumExpectedChunksPerShard []int64 `json:"num_expected_chunks_per_shard"`
+	// Per shard stats, in the same order as ShardsEndorsed
+	NumProducedEndorsementsPerShard []int64 `json:"num_produced_endorsements_per_shard"`
+	NumExpectedEndorsementsPerShard []int64 `json:"num_expected_endorsements_per_shard"`
+}
+
+// ShardStats holds the production and endorsement stats of a validator on a
+// single shard.
+type ShardStats struct {
+	ShardID              int
+	ProducedChunks       int64
+	ExpectedChunks       int64
+	ProducedEndorsements int64
+	ExpectedEndorsements int64
+}
+
+// IsChunkValidatorOnly returns true when the validator neither produces blocks
+// nor chunks and only endorses chunks.
+func (v CurrentEpochValidatorInfo) IsChunkValidatorOnly() bool {
+	return len(v.Shards) == 0 && v.NumExpectedBlocks == 0 && v.NumExpectedChunks == 0
+}
+
+// ShardStats returns the stats of the validator on every shard it produces or
+// endorses chunks for, sorted by shard.
+func (v CurrentEpochValidatorInfo) ShardStats() []ShardStats {
+	stats := make(map[int]*ShardStats, len(v.Shards)+len(v.ShardsEndorsed))
+	get := func(shardID int) *ShardStats {
+		if _, ok := stats[shardID]; !ok {
+			stats[shardID] = &ShardStats{ShardID: shardID}
+		}
+		return stats[shardID]
+	}
+
+	for i, shardID := range v.Shards {
+		s := get(shardID)
+		if i < len(v.NumProducedChunksPerShard) {
+			s.ProducedChunks = v.NumProducedChunksPerShard[i]
+		}
+		if i < len(v.NumExpectedChunksPerShard) {
+			s.ExpectedChunks = v.NumExpectedChunksPerShard[i]
+		}
+	}
+	for i, shardID := range v.ShardsEndorsed {
+		s := get(shardID)
+		if i < len(v.NumProducedEndorsementsPerShard) {
+			s.ProducedEndorsements = v.NumProducedEndorsementsPerShard[i]
+		}
+		if i < len(v.NumExpectedEndorsementsPerShard) {
+			s.ExpectedEndorsements = v.NumExpectedEndorsementsPerShard[i]
+		}
+	}
+
+	result := make([]ShardStats, 0, len(stats))
+	for _, s := range stats {
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ShardID < result[j].ShardID
+	})
+	return result
+}
+
+// MissedChunksPerShard returns the number of chunks missed on each shard of the validator.
+func (v CurrentEpochValidatorInfo) MissedChunksPerShard() map[int]int64 {
+	missed := make(map[int]int64, len(v.Shards))
+	for i, shardID := range v.Shards {
+		if i < len(v.NumExpectedChunksPerShard) && i < len(v.NumProducedChunksPerShard) {
+			missed[shardID] = v.NumExpectedChunksPerShard[i] - v.NumProducedChunksPerShard[i]
+		}
+	}
+	return missed
+}
+
+// BlocksUptime returns the produced blocks in percent of the expected ones.
+func (v CurrentEpochValidatorInfo) BlocksUptime() float64 {
+	return uptimePercent(v.NumProducedBlocks, v.NumExpectedBlocks)
+}
+
+// ChunksUptime returns the produced chunks in percent of the expected ones.
+func (v CurrentEpochValidatorInfo) ChunksUptime() float64 {
+	return uptimePercent(v.NumProducedChunks, v.NumExpectedChunks)
+}
+
+// EndorsementsUptime returns the produced endorsements in percent of the
+// expected ones.
+func (v CurrentEpochValidatorInfo) EndorsementsUptime() float64 {
+	return uptimePercent(v.NumProducedEndorsements, v.NumExpectedEndorsements)
+}
+
+// uptimePercent is 100 when nothing is expected.
+func uptimePercent(produced int64, expected int64) float64 {
+	if expected == 0 {
+		return 100
+	}
+	return 100 * float64(produced) / float64(expected)
 }
 
 type Validator struct {
@@ -0,0 +1,56 @@
+package near
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurrentEpochValidatorInfo(t *testing.T) {
+	t.Run("Chunk validator only", func(t *testing.T) {
+		var v CurrentEpochValidatorInfo
+		err := json.Unmarshal([]byte(`{
+			"account_id": "validator.pool.near",
+			"num_expected_blocks": 0,
+			"num_expected_chunks": 0,
+			"num_expected_endorsements": 300,
+			"num_expected_endorsements_per_shard": [100, 200],
+			"num_produced_blocks": 0,
+			"num_produced_chunks": 0,
+			"num_produced_endorsements": 270,
+			"num_produced_endorsements_per_shard": [90, 180],
+			"shards": [],
+			"shards_endorsed": [3, 1],
+			"stake": "1000"
+		}`), &v)
+		require.NoError(t, err)
+
+		assert.True(t, v.IsChunkValidatorOnly())
+		assert.Equal(t, float64(90), v.EndorsementsUptime())
+		assert.Equal(t, []ShardStats{
+			{ShardID: 1, ProducedEndorsements: 180, ExpectedEndorsements: 200},
+			{ShardID: 3, ProducedEndorsements: 90, ExpectedEndorsements: 100},
+		}, v.ShardStats())
+	})
+
+	t.Run("Chunk producer", func(t *testing.T) {
+		v := CurrentEpochValidatorInfo{
+			Shards:                          []int{0},
+			ShardsEndorsed:                  []int{0, 2},
+			NumExpectedBlocks:               10,
+			NumExpectedChunks:               40,
+			NumProducedChunksPerShard:       []int64{38},
+			NumExpectedChunksPerShard:       []int64{40},
+			NumProducedEndorsementsPerShard: []int64{50, 60},
+			NumExpectedEndorsementsPerShard: []int64{50, 61},
+		}
+
+		assert.False(t, v.IsChunkValidatorOnly())
+		assert.Equal(t, []ShardStats{
+			{ShardID: 0, ProducedChunks: 38, ExpectedChunks: 40, ProducedEndorsements: 50, ExpectedEndorsements: 50},
+			{ShardID: 2, ProducedEndorsements: 60, ExpectedEndorsements: 61},
+		}, v.ShardStats())
+	})
+}
@@ -0,0 +1,26 @@
+package near
+
+import (
+	"context"
+
+	"github.com/shopspring/decimal"
+)
+
+// ViewAccountResponse holds the balances of an account, in yoctoNEAR.
+type ViewAccountResponse struct {
+	QueryResponse
+	Amount       decimal.Decimal `json:"amount"`
+	Locked       decimal.Decimal `json:"locked"`
+	CodeHash     string          `json:"code_hash"`
+	StorageUsage int64           `json:"storage_usage"`
+}
+
+func (c *Client) ViewAccount(ctx context.Context, accountID string, opts ...QueryOption) (ViewAccountResponse, error) {
+	var resp ViewAccountResponse
+	req, err := NewQueryRequest("view_account", accountID, "", opts...)
+	if err != nil {
+		return resp, err
+	}
+	err = c.call(ctx, "query", req, &resp)
+	return resp, err
+}
@@ -0,0 +1,149 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/history"
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/shopspring/decimal"
+)
+
+var yoctoUnit = decimal.NewFromInt(10).Pow(decimal.NewFromInt(24))
+
+// Report holds the final performance of a tracked validator over an epoch.
+// Stakes are in yoctoNEAR and uptimes in percent.
+type Report struct {
+	Network          string    `json:"network"`
+	AccountID        string    `json:"account_id"`
+	EpochHeight      int64     `json:"epoch_height"`
+	EpochStartHeight int64     `json:"epoch_start_height"`
+	LastBlockHeight  int64     `json:"last_block_height"`
+	GeneratedAt      time.Time `json:"generated_at"`
+
+	// IsValidator is false when the account wasn't a validator of the epoch,
+	// the stats are then left empty
+	IsValidator bool            `json:"is_validator"`
+	Rank        int             `json:"rank,omitempty"`
+	Validators  int             `json:"validators"`
+	Stake       decimal.Decimal `json:"stake"`
+	IsSlashed   bool            `json:"is_slashed"`
+
+	NumProducedBlocks       int64   `json:"num_produced_blocks"`
+	NumExpectedBlocks       int64   `json:"num_expected_blocks"`
+	NumProducedChunks       int64   `json:"num_produced_chunks"`
+	NumExpectedChunks       int64   `json:"num_expected_chunks"`
+	NumProducedEndorsements int64   `json:"num_produced_endorsements"`
+	NumExpectedEndorsements int64   `json:"num_expected_endorsements"`
+	UptimeBlocks            float64 `json:"uptime_blocks"`
+	UptimeChunks            float64 `json:"uptime_chunks"`
+	UptimeEndorsements      float64 `json:"uptime_endorsements"`
+
+	KickedOut     bool   `json:"kicked_out"`
+	KickoutReason string `json:"kickout_reason,omitempty"`
+
+	InNextEpoch bool             `json:"in_next_epoch"`
+	NextStake   *decimal.Decimal `json:"next_stake,omitempty"`
+}
+
+// New builds the report of the account from the final stats of an epoch and
+// the validators of the epoch that follows it.
+func New(network string, epoch history.Epoch, next near.ValidatorsResponse, accountID string) Report {
+	report := Report{
+		Network:          network,
+		AccountID:        accountID,
+		EpochHeight:      epoch.EpochHeight,
+		EpochStartHeight: epoch.EpochStartHeight,
+		LastBlockHeight:  epoch.LastBlockHeight,
+		GeneratedAt:      time.Now().UTC(),
+		Validators:       len(epoch.Validators),
+	}
+
+	ranked := make([]near.CurrentEpochValidatorInfo, len(epoch.Validators))
+	copy(ranked, epoch.Validators)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Stake.GreaterThan(ranked[j].Stake)
+	})
+
+	for i, v := range ranked {
+		if v.AccountId != accountID {
+			continue
+		}
+		report.IsValidator = true
+		report.Rank = i + 1
+		report.Stake = v.Stake
+		report.IsSlashed = v.IsSlashed
+		report.NumProducedBlocks = v.NumProducedBlocks
+		report.NumExpectedBlocks = v.NumExpectedBlocks
+		report.NumProducedChunks = v.NumProducedChunks
+		report.NumExpectedChunks = v.NumExpectedChunks
+		report.NumProducedEndorsements = v.NumProducedEndorsements
+		report.NumExpectedEndorsements = v.NumExpectedEndorsements
+		report.UptimeBlocks = v.BlocksUptime()
+		report.UptimeChunks = v.ChunksUptime()
+		report.UptimeEndorsements = v.EndorsementsUptime()
+		break
+	}
+
+	for _, v := range next.PrevEpochKickOut {
+		if v.AccountId == accountID {
+			report.KickedOut = true
+			report.KickoutReason = v.Reason.String()
+			break
+		}
+	}
+
+	for _, v := range next.CurrentValidators {
+		if v.AccountId == accountID {
+			stake := v.Stake
+			report.InNextEpoch = true
+			report.NextStake = &stake
+			break
+		}
+	}
+
+	return report
+}
+
+// Markdown renders the report as a Markdown document.
+func (r Report) Markdown() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s - epoch %d (%s)\n\n", r.AccountID, r.EpochHeight, r.Network)
+	fmt.Fprintf(&b, "Blocks %d to %d, generated at %s.\n\n", r.EpochStartHeight, r.LastBlockHeight, r.GeneratedAt.Format(time.RFC3339))
+
+	if !r.IsValidator {
+		b.WriteString("Not a validator during this epoch.\n\n")
+	} else {
+		b.WriteString("|              | Produced | Expected | Uptime  |\n")
+		b.WriteString("|--------------|----------|----------|---------|\n")
+		fmt.Fprintf(&b, "| Blocks       | %d | %d | %.2f%% |\n", r.NumProducedBlocks, r.NumExpectedBlocks, r.UptimeBlocks)
+		fmt.Fprintf(&b, "| Chunks       | %d | %d | %.2f%% |\n", r.NumProducedChunks, r.NumExpectedChunks, r.UptimeChunks)
+		fmt.Fprintf(&b, "| Endorsements | %d | %d | %.2f%% |\n\n", r.NumProducedEndorsements, r.NumExpectedEndorsements, r.UptimeEndorsements)
+
+		fmt.Fprintf(&b, "- **Rank:** %d / %d\n", r.Rank, r.Validators)
+		fmt.Fprintf(&b, "- **Stake:** %s NEAR\n", formatNEAR(r.Stake))
+		if r.IsSlashed {
+			b.WriteString("- **Slashed:** yes\n")
+		}
+	}
+
+	if r.KickedOut {
+		fmt.Fprintf(&b, "- **Kicked out:** yes (%s)\n", r.KickoutReason)
+	} else {
+		b.WriteString("- **Kicked out:** no\n")
+	}
+	if r.InNextEpoch {
+		fmt.Fprintf(&b, "- **Next epoch:** validator with %s NEAR\n", formatNEAR(*r.NextStake))
+	} else {
+		b.WriteString("- **Next epoch:** not a validator\n")
+	}
+
+	return b.String()
+}
+
+func formatNEAR(yocto decimal.Decimal) string {
+	return yocto.Div(yoctoUnit).StringFixed(2)
+}
@@ -0,0 +1,109 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/alert"
+	"github.com/kilnfi/near-validator-watcher/pkg/history"
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport(t *testing.T) {
+	var epoch history.Epoch
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"epoch_height": 2000,
+		"epoch_start_height": 100,
+		"last_block_height": 199,
+		"validators": [
+			{"account_id": "node1", "stake": "3000000000000000000000000000"},
+			{"account_id": "kiln.poolv1.near", "stake": "2000000000000000000000000000", "num_produced_blocks": 9, "num_expected_blocks": 10}
+		]
+	}`), &epoch))
+
+	var next near.ValidatorsResponse
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"current_validators": [{"account_id": "node1", "stake": "3000000000000000000000000000"}],
+		"prev_epoch_kickout": [{"account_id": "kiln.poolv1.near", "reason": {"NotEnoughBlocks": {"produced": 9, "expected": 10}}}]
+	}`), &next))
+
+	report := New("mainnet", epoch, next, "kiln.poolv1.near")
+	assert.True(t, report.IsValidator)
+	assert.Equal(t, 2, report.Rank)
+	assert.Equal(t, float64(90), report.UptimeBlocks)
+	assert.True(t, report.KickedOut)
+	assert.False(t, report.InNextEpoch)
+	assert.Contains(t, report.Markdown(), "- **Stake:** 2000.00 NEAR")
+
+	assert.False(t, New("mainnet", epoch, next, "unknown").IsValidator)
+
+	dir := t.TempDir()
+	writer, err := NewWriter(dir)
+	require.NoError(t, err)
+	assert.False(t, writer.Has(2000, "kiln.poolv1.near"))
+	require.NoError(t, writer.Write(report))
+	assert.True(t, writer.Has(2000, "kiln.poolv1.near"))
+	assert.FileExists(t, filepath.Join(dir, "epoch-2000-kiln.poolv1.near.md"))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestWriterWebhook(t *testing.T) {
+	var (
+		failing  atomic.Bool
+		received = make(chan Report, 1)
+		server   = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			if failing.Load() {
+				res.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			var report Report
+			_ = json.NewDecoder(req.Body).Decode(&report)
+			received <- report
+		}))
+	)
+	defer server.Close()
+
+	dir := t.TempDir()
+	writer, err := NewWriter(dir, WithWebhook(alert.NewWebhook(server.URL, alert.WithRetry(1, 0))))
+	require.NoError(t, err)
+
+	report := Report{Network: "mainnet", EpochHeight: 2000, AccountID: "kiln.poolv1.near"}
+	sent := filepath.Join(dir, "epoch-2000-kiln.poolv1.near.sent")
+
+	// A failed post is retried at the next delivery
+	failing.Store(true)
+	require.NoError(t, writer.Write(report))
+	writer.deliver(context.Background())
+	assert.True(t, writer.Has(2000, "kiln.poolv1.near"))
+	assert.NoFileExists(t, sent)
+
+	failing.Store(false)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = writer.Start(ctx)
+	}()
+
+	select {
+	case posted := <-received:
+		assert.Equal(t, report.AccountID, posted.AccountID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for report")
+	}
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(sent)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,161 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/alert"
+	"github.com/sirupsen/logrus"
+)
+
+// deliveryInterval is how often the reports not posted to the webhook yet are
+// retried.
+const deliveryInterval = time.Minute
+
+// Writer writes reports as Markdown and JSON files in a directory, and
+// optionally posts them to a webhook.
+type Writer struct {
+	dir     string
+	webhook *alert.Webhook
+	written chan struct{}
+}
+
+type Option func(*Writer)
+
+// WithWebhook posts every written report as JSON to the webhook, once Start
+// is running.
+func WithWebhook(webhook *alert.Webhook) Option {
+	return func(w *Writer) {
+		w.webhook = webhook
+	}
+}
+
+// NewWriter creates the directory of the reports if needed.
+func NewWriter(dir string, options ...Option) (*Writer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	writer := &Writer{dir: dir, written: make(chan struct{}, 1)}
+	for _, option := range options {
+		option(writer)
+	}
+	return writer, nil
+}
+
+func (w *Writer) path(epochHeight int64, accountID, ext string) string {
+	return filepath.Join(w.dir, "epoch-"+strconv.FormatInt(epochHeight, 10)+"-"+accountID+ext)
+}
+
+// Has returns whether the report of the account for the epoch is written.
+func (w *Writer) Has(epochHeight int64, accountID string) bool {
+	_, err := os.Stat(w.path(epochHeight, accountID, ".json"))
+	return err == nil
+}
+
+// Write writes the Markdown then the JSON file of the report, the report is
+// posted to the webhook if any in the background.
+func (w *Writer) Write(report Report) error {
+	content, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// The JSON file is written last as Has relies on it
+	if err := writeFile(w.path(report.EpochHeight, report.AccountID, ".md"), []byte(report.Markdown())); err != nil {
+		return err
+	}
+	if err := writeFile(w.path(report.EpochHeight, report.AccountID, ".json"), content); err != nil {
+		return err
+	}
+
+	select {
+	case w.written <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Start posts the written reports to the webhook until the context is
+// cancelled. A report is marked as sent once posted, the ones not sent yet,
+// including before a restart, are retried periodically.
+func (w *Writer) Start(ctx context.Context) error {
+	if w.webhook == nil {
+		return nil
+	}
+
+	ticker := time.NewTicker(deliveryInterval)
+	defer ticker.Stop()
+
+	for {
+		w.deliver(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-w.written:
+		}
+	}
+}
+
+// deliver posts every report without sent marker to the webhook.
+func (w *Writer) deliver(ctx context.Context) {
+	paths, err := filepath.Glob(filepath.Join(w.dir, "epoch-*.json"))
+	if err != nil {
+		logrus.WithError(err).Error("failed to list reports")
+		return
+	}
+
+	for _, path := range paths {
+		sent := strings.TrimSuffix(path, ".json") + ".sent"
+		if _, err := os.Stat(sent); err == nil {
+			continue
+		}
+
+		logger := logrus.WithField("report", filepath.Base(path))
+		content, err := os.ReadFile(path)
+		if err != nil {
+			logger.WithError(err).Error("failed to read report")
+			continue
+		}
+		if err := w.webhook.Send(ctx, json.RawMessage(content)); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.WithError(err).Error("failed to post report")
+			continue
+		}
+		if err := writeFile(sent, nil); err != nil {
+			logger.WithError(err).Error("failed to mark report as sent")
+			continue
+		}
+		logger.Info("posted report")
+	}
+}
+
+// writeFile writes to a temporary file first so that a crash never leaves a
+// partial file.
+func writeFile(path string, content []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
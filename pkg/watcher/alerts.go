@@ -0,0 +1,139 @@
+package watcher
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/alert"
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+)
+
+const (
+	alertNodeSyncing        = "node_syncing"
+	alertRPCUnreachable     = "rpc_unreachable"
+	alertValidatorKickedOut = "validator_kicked_out"
+	alertValidatorNotInNext = "validator_not_in_next_validators"
+	alertValidatorUptimeLow = "validator_uptime_low"
+)
+
+const defaultAlertUptimeThreshold = 90
+
+// alertKey returns the key deduplicating the alert, prefixed by the network
+// so that networks watched by the same process don't collide.
+func (c *Config) alertKey(name string, accountID string) string {
+	parts := make([]string, 0, 3)
+	if c.Network != "" {
+		parts = append(parts, c.Network)
+	}
+	parts = append(parts, name)
+	if accountID != "" {
+		parts = append(parts, accountID)
+	}
+	return strings.Join(parts, "/")
+}
+
+// evaluateRPCAlert fires an alert when data can't be collected because the RPC
+// endpoints can't be reached.
+func (w *Watcher) evaluateRPCAlert(err error) {
+	var rpcErr *near.Error
+	if err == nil {
+		w.config.Alerts.Resolve(w.config.alertKey(alertRPCUnreachable, ""))
+	} else if !errors.As(err, &rpcErr) {
+		w.config.Alerts.Fire(alert.Alert{
+			Name:     alertRPCUnreachable,
+			Key:      w.config.alertKey(alertRPCUnreachable, ""),
+			Severity: alert.SeverityCritical,
+			Summary:  fmt.Sprintf("RPC unreachable: %s", err),
+			Labels:   w.config.alertLabels(""),
+		})
+	}
+}
+
+func (w *Watcher) evaluateAlerts(status near.StatusResponse, validators near.ValidatorsResponse) {
+	if w.config.Alerts == nil {
+		return
+	}
+
+	if status.SyncInfo.Syncing {
+		w.config.Alerts.Fire(alert.Alert{
+			Name:     alertNodeSyncing,
+			Key:      w.config.alertKey(alertNodeSyncing, ""),
+			Severity: alert.SeverityWarning,
+			Summary:  fmt.Sprintf("Node is syncing (block #%d)", status.SyncInfo.LatestBlockHeight),
+			Labels:   w.config.alertLabels(""),
+		})
+	} else {
+		w.config.Alerts.Resolve(w.config.alertKey(alertNodeSyncing, ""))
+	}
+
+	for _, account := range w.config.TrackedAccounts {
+		labels := w.config.alertLabels(account)
+		threshold := w.config.uptimeThreshold(account)
+
+		// Uptime
+		uptimeLow := false
+		for _, v := range validators.CurrentValidators {
+			if v.AccountId != account {
+				continue
+			}
+			uptimeBlocks, uptimeChunks := uptime(v)
+			if uptimeBlocks < threshold || uptimeChunks < threshold {
+				uptimeLow = true
+				w.config.Alerts.Fire(alert.Alert{
+					Name:     alertValidatorUptimeLow,
+					Key:      w.config.alertKey(alertValidatorUptimeLow, account),
+					Severity: alert.SeverityWarning,
+					Summary: fmt.Sprintf("Validator %s uptime below %s%% (blocks %s%%, chunks %s%%)",
+						account,
+						prettyPrintFloat(threshold),
+						prettyPrintFloat(uptimeBlocks),
+						prettyPrintFloat(uptimeChunks),
+					),
+					Labels: labels,
+				})
+			}
+		}
+		if !uptimeLow {
+			w.config.Alerts.Resolve(w.config.alertKey(alertValidatorUptimeLow, account))
+		}
+
+		// Kickout
+		kickedOut := false
+		for _, v := range validators.PrevEpochKickOut {
+			if v.AccountId != account {
+				continue
+			}
+			kickedOut = true
+			w.config.Alerts.Fire(alert.Alert{
+				Name:     alertValidatorKickedOut,
+				Key:      w.config.alertKey(alertValidatorKickedOut, account),
+				Severity: alert.SeverityCritical,
+				Summary:  fmt.Sprintf("Validator %s was kicked out in the previous epoch: %s", account, v.Reason),
+				Labels:   labels,
+			})
+		}
+		if !kickedOut {
+			w.config.Alerts.Resolve(w.config.alertKey(alertValidatorKickedOut, account))
+		}
+
+		// Next validators
+		inNext := false
+		for _, v := range validators.NextValidators {
+			if v.AccountId == account {
+				inNext = true
+			}
+		}
+		if inNext {
+			w.config.Alerts.Resolve(w.config.alertKey(alertValidatorNotInNext, account))
+		} else {
+			w.config.Alerts.Fire(alert.Alert{
+				Name:     alertValidatorNotInNext,
+				Key:      w.config.alertKey(alertValidatorNotInNext, account),
+				Severity: alert.SeverityCritical,
+				Summary:  fmt.Sprintf("Validator %s is not part of the next epoch validators", account),
+				Labels:   labels,
+			})
+		}
+	}
+}
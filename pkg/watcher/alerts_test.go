@@ -0,0 +1,148 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/alert"
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// notifications records the alerts delivered by the manager, in order.
+type notifications chan alert.Alert
+
+func (n notifications) Notify(_ context.Context, alert alert.Alert) error {
+	n <- alert
+	return nil
+}
+
+// next returns the next notification, an alert deduplicated by the manager
+// shows up as the following notification being received instead.
+func (n notifications) next(t *testing.T) alert.Alert {
+	t.Helper()
+	select {
+	case alert := <-n:
+		return alert
+	case <-time.After(time.Second):
+		require.FailNow(t, "no alert sent")
+	}
+	return alert.Alert{}
+}
+
+func TestEvaluateAlerts(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		sent    = make(notifications, 10)
+		alerts  = alert.NewManager([]alert.Notifier{sent})
+		watcher = New(nil, nil, &Config{
+			Network:         "mainnet",
+			TrackedAccounts: []string{"kiln.poolv1.near", "other.poolv1.near"},
+			Alerts:          alerts,
+		})
+	)
+	go func() { _ = alerts.Start(ctx) }()
+
+	validator := func(account string, produced int64) near.CurrentEpochValidatorInfo {
+		var v near.CurrentEpochValidatorInfo
+		v.AccountId = account
+		v.NumProducedBlocks, v.NumExpectedBlocks = produced, 100
+		v.NumProducedChunks, v.NumExpectedChunks = 100, 100
+		return v
+	}
+	next := func(accounts ...string) near.ValidatorsResponse {
+		var resp near.ValidatorsResponse
+		resp.NextValidators = make([]struct {
+			near.Validator
+			Shards []int `json:"shards"`
+		}, len(accounts))
+		for i, account := range accounts {
+			resp.NextValidators[i].AccountId = account
+		}
+		return resp
+	}
+
+	var status near.StatusResponse
+
+	t.Run("Firing", func(t *testing.T) {
+		validators := next("kiln.poolv1.near", "other.poolv1.near")
+		validators.CurrentValidators = []near.CurrentEpochValidatorInfo{
+			validator("kiln.poolv1.near", 80),
+			validator("other.poolv1.near", 100),
+		}
+		watcher.evaluateAlerts(status, validators)
+
+		a := sent.next(t)
+		assert.Equal(t, alertValidatorUptimeLow, a.Name)
+		assert.Equal(t, "mainnet/validator_uptime_low/kiln.poolv1.near", a.Key)
+		assert.Equal(t, alert.StatusFiring, a.Status)
+		assert.Equal(t, "Validator kiln.poolv1.near uptime below 90% (blocks 80%, chunks 100%)", a.Summary)
+		assert.Equal(t, map[string]string{"account_id": "kiln.poolv1.near", "network": "mainnet"}, a.Labels)
+	})
+
+	t.Run("Deduplicate per validator", func(t *testing.T) {
+		validators := next("kiln.poolv1.near")
+		validators.CurrentValidators = []near.CurrentEpochValidatorInfo{
+			validator("kiln.poolv1.near", 70),
+			validator("other.poolv1.near", 80),
+		}
+		watcher.evaluateAlerts(status, validators)
+
+		// Still firing for kiln, only the new alerts of the other validator
+		// are sent
+		a := sent.next(t)
+		assert.Equal(t, "mainnet/validator_uptime_low/other.poolv1.near", a.Key)
+		a = sent.next(t)
+		assert.Equal(t, "mainnet/validator_not_in_next_validators/other.poolv1.near", a.Key)
+		assert.Equal(t, alert.SeverityCritical, a.Severity)
+		assert.Len(t, alerts.Firing(), 3)
+	})
+
+	t.Run("Resolving", func(t *testing.T) {
+		validators := next("kiln.poolv1.near", "other.poolv1.near")
+		validators.CurrentValidators = []near.CurrentEpochValidatorInfo{
+			validator("kiln.poolv1.near", 100),
+			validator("other.poolv1.near", 80),
+		}
+		status.SyncInfo.Syncing = true
+		status.SyncInfo.LatestBlockHeight = 1000
+		watcher.evaluateAlerts(status, validators)
+
+		a := sent.next(t)
+		assert.Equal(t, "mainnet/node_syncing", a.Key)
+		assert.Equal(t, alert.StatusFiring, a.Status)
+		assert.Equal(t, "Node is syncing (block #1000)", a.Summary)
+
+		a = sent.next(t)
+		assert.Equal(t, "mainnet/validator_uptime_low/kiln.poolv1.near", a.Key)
+		assert.Equal(t, alert.StatusResolved, a.Status)
+		assert.NotNil(t, a.EndsAt)
+
+		a = sent.next(t)
+		assert.Equal(t, "mainnet/validator_not_in_next_validators/other.poolv1.near", a.Key)
+		assert.Equal(t, alert.StatusResolved, a.Status)
+	})
+
+	t.Run("RPC down", func(t *testing.T) {
+		// Request errors mean the endpoint is reachable
+		watcher.evaluateRPCAlert(&near.Error{Name: "HANDLER_ERROR"})
+		watcher.evaluateRPCAlert(errors.New("connection refused"))
+
+		a := sent.next(t)
+		assert.Equal(t, "mainnet/rpc_unreachable", a.Key)
+		assert.Equal(t, alert.StatusFiring, a.Status)
+		assert.Equal(t, "RPC unreachable: connection refused", a.Summary)
+
+		watcher.evaluateRPCAlert(errors.New("connection refused"))
+		watcher.evaluateRPCAlert(nil)
+
+		a = sent.next(t)
+		assert.Equal(t, "mainnet/rpc_unreachable", a.Key)
+		assert.Equal(t, alert.StatusResolved, a.Status)
+	})
+}
@@ -0,0 +1,180 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/sirupsen/logrus"
+)
+
+// maxBlocksPerCycle limits how many blocks are fetched in a single collection
+// cycle so that catching up after a long outage doesn't stall the watcher.
+const maxBlocksPerCycle = 200
+
+// blockFetchConcurrency is how many blocks are fetched at once.
+const blockFetchConcurrency = 4
+
+// blockFollower walks every final block since the last collection cycle to
+// find skipped heights and attributes them to the validators who missed them.
+//
+// The RPC doesn't expose which validator is expected to produce a given
+// height, so skipped heights are matched with the validators whose amount of
+// missed blocks (expected - produced) increased since the previous cycle.
+type blockFollower struct {
+	follower *near.Follower
+
+	// Skipped heights not attributed to a validator yet
+	pendingHeights []uint64
+
+	epochStartHeight int64
+	missedBlocks     map[string]int64
+	missedChunks     map[string]map[int]int64
+}
+
+// missedBlock holds the blocks missed by a validator since the previous cycle.
+type missedBlock struct {
+	AccountID string
+	Count     int64
+	// Heights are the exact skipped heights the validator missed, only known
+	// when it is the single validator which missed blocks since the previous
+	// cycle.
+	Heights []uint64
+}
+
+// missedChunk holds the chunks missed by a validator on a shard since the
+// previous cycle.
+type missedChunk struct {
+	AccountID string
+	ShardID   int
+	Count     int64
+}
+
+// shardChunk tells whether the chunk of a shard was included in a block.
+type shardChunk struct {
+	ShardID  int
+	Included bool
+}
+
+// walk follows the final blocks since the previous call, so that skipped
+// heights are never reorganized. The follower is restarted at the final head
+// when the chain doesn't match the blocks already walked.
+func (f *blockFollower) walk(ctx context.Context, client *near.Client) ([]near.BlockResponse, error) {
+	if f.follower == nil {
+		f.follower = near.NewFollower(client,
+			near.WithConcurrency(blockFetchConcurrency),
+			near.WithBatchSize(maxBlocksPerCycle),
+			near.WithMaxLag(maxBlocksPerCycle),
+		)
+	}
+
+	fetched, err := f.follower.Poll(ctx)
+	if errors.Is(err, near.ErrChainMismatch) {
+		logrus.WithError(err).Warn("restarting block walk at the final head")
+		f.follower = nil
+	}
+
+	blocks := make([]near.BlockResponse, 0, len(fetched))
+	for _, block := range fetched {
+		if block.Skipped {
+			f.pendingHeights = append(f.pendingHeights, block.Height)
+		} else {
+			blocks = append(blocks, block.Block)
+		}
+	}
+
+	return blocks, err
+}
+
+// blockChunks returns for every shard whether its chunk was included in the block.
+// The chunk mask is used when available, otherwise the chunk inclusion height
+// is compared with the block height.
+func blockChunks(block near.BlockResponse) []shardChunk {
+	chunks := make([]shardChunk, 0, len(block.Chunks))
+	for i, chunk := range block.Chunks {
+		included := chunk.HeightIncluded == block.Header.Height
+		if i < len(block.Header.ChunkMask) {
+			included = block.Header.ChunkMask[i]
+		}
+		chunks = append(chunks, shardChunk{ShardID: chunk.ShardID, Included: included})
+	}
+	return chunks
+}
+
+// attribute matches the pending skipped heights with the validators whose
+// amount of missed blocks increased since the previous call, and returns the
+// chunks missed by every validator on each of its shards. When several
+// validators missed blocks, the skipped heights can't be told apart and are
+// returned as unattributed.
+func (f *blockFollower) attribute(validators near.ValidatorsResponse) ([]missedBlock, []missedChunk, []uint64) {
+	var (
+		missedBlocks = make(map[string]int64, len(validators.CurrentValidators))
+		missedChunks = make(map[string]map[int]int64, len(validators.CurrentValidators))
+	)
+	for _, v := range validators.CurrentValidators {
+		missedBlocks[v.AccountId] = v.NumExpectedBlocks - v.NumProducedBlocks
+		missedChunks[v.AccountId] = v.MissedChunksPerShard()
+	}
+
+	// Counters are reset at every epoch
+	if f.missedBlocks == nil || f.epochStartHeight != validators.EpochStartHeight {
+		if len(f.pendingHeights) > 0 {
+			logrus.WithField("heights", f.pendingHeights).Warn("skipped blocks could not be attributed before epoch change")
+		}
+		f.epochStartHeight = validators.EpochStartHeight
+		f.missedBlocks = missedBlocks
+		f.missedChunks = missedChunks
+		f.pendingHeights = nil
+		return nil, nil, nil
+	}
+
+	var (
+		blocks = make([]missedBlock, 0)
+		total  int64
+	)
+	for accountID, count := range missedBlocks {
+		if delta := count - f.missedBlocks[accountID]; delta > 0 {
+			blocks = append(blocks, missedBlock{AccountID: accountID, Count: delta})
+			total += delta
+		}
+	}
+
+	chunks := make([]missedChunk, 0)
+	for accountID, shards := range missedChunks {
+		for shardID, count := range shards {
+			if delta := count - f.missedChunks[accountID][shardID]; delta > 0 {
+				chunks = append(chunks, missedChunk{AccountID: accountID, ShardID: shardID, Count: delta})
+			}
+		}
+	}
+
+	f.missedBlocks = missedBlocks
+	f.missedChunks = missedChunks
+
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].AccountID < blocks[j].AccountID
+	})
+	sort.Slice(chunks, func(i, j int) bool {
+		if chunks[i].AccountID == chunks[j].AccountID {
+			return chunks[i].ShardID < chunks[j].ShardID
+		}
+		return chunks[i].AccountID < chunks[j].AccountID
+	})
+
+	// Skipped heights are consumed in order, validators counters may lag a
+	// few blocks behind the walked heights.
+	n := int(total)
+	if n > len(f.pendingHeights) {
+		n = len(f.pendingHeights)
+	}
+	heights := f.pendingHeights[:n]
+	f.pendingHeights = f.pendingHeights[n:]
+
+	if len(blocks) == 1 {
+		blocks[0].Heights = heights
+		return blocks, chunks, nil
+	}
+
+	return blocks, chunks, heights
+}
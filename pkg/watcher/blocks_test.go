@@ -0,0 +1,211 @@
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newValidatorsResponse(epochStartHeight int64, blocks map[string][2]int64) near.ValidatorsResponse {
+	var validators near.ValidatorsResponse
+	validators.EpochStartHeight = epochStartHeight
+	for accountID, b := range blocks {
+		v := near.CurrentEpochValidatorInfo{}
+		v.AccountId = accountID
+		v.NumProducedBlocks = b[0]
+		v.NumExpectedBlocks = b[1]
+		validators.CurrentValidators = append(validators.CurrentValidators, v)
+	}
+	return validators
+}
+
+// blockChain serves the block method from blocks authored by height, other
+// heights being skipped.
+type blockChain struct {
+	mu      sync.Mutex
+	head    uint64
+	authors map[uint64]string
+	prev    map[uint64]uint64
+	failing bool
+}
+
+func (c *blockChain) produce(height uint64, prevHeight uint64, author string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.authors[height] = author
+	c.prev[height] = prevHeight
+	c.head = height
+}
+
+func (c *blockChain) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var payload struct {
+		Params near.BlockRequest `json:"params"`
+	}
+	_ = json.NewDecoder(req.Body).Decode(&payload)
+
+	height := c.head
+	if payload.Params.Finality == "" {
+		height = uint64(payload.Params.BlockID.(float64))
+	}
+	author, ok := c.authors[height]
+	switch {
+	case c.failing:
+		_, _ = res.Write([]byte(`{"jsonrpc": "2.0", "id": "dontcare", "error": {"name": "INTERNAL_ERROR", "code": -32000}}`))
+	case !ok:
+		_, _ = res.Write([]byte(`{"jsonrpc": "2.0", "id": "dontcare", "error": {"name": "HANDLER_ERROR", "code": -32000, "cause": {"name": "UNKNOWN_BLOCK"}}}`))
+	default:
+		_, _ = fmt.Fprintf(res, `{"jsonrpc": "2.0", "id": "dontcare", "result": {"author": %q, "header": {"height": %d, "hash": "hash%d", "prev_height": %d, "prev_hash": "hash%d"}}}`,
+			author, height, height, c.prev[height], c.prev[height])
+	}
+}
+
+func TestBlockFollower(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Walk blocks", func(t *testing.T) {
+		var (
+			chain  = &blockChain{authors: map[uint64]string{}, prev: map[uint64]uint64{}}
+			server = httptest.NewServer(chain)
+			client = near.NewClient([]string{server.URL})
+			f      = blockFollower{}
+		)
+		defer server.Close()
+
+		chain.produce(100, 99, "node1")
+		blocks, err := f.walk(ctx, client)
+		require.NoError(t, err)
+		require.Len(t, blocks, 1)
+		assert.Equal(t, "node1", blocks[0].Author)
+
+		// Height 103 is skipped
+		chain.produce(101, 100, "node2")
+		chain.produce(102, 101, "node1")
+		chain.produce(104, 102, "node2")
+		chain.produce(105, 104, "node1")
+		blocks, err = f.walk(ctx, client)
+		require.NoError(t, err)
+		assert.Len(t, blocks, 4)
+		assert.Equal(t, []uint64{103}, f.pendingHeights)
+
+		chain.produce(106, 105, "node2")
+		chain.failing = true
+		_, err = f.walk(ctx, client)
+		require.Error(t, err)
+		assert.Equal(t, near.Cursor{Height: 105, Hash: "hash105"}, f.follower.Cursor())
+	})
+
+	t.Run("Attribute missed blocks", func(t *testing.T) {
+		f := blockFollower{}
+
+		// First call only initializes counters
+		missed, _, _ := f.attribute(newValidatorsResponse(1000, map[string][2]int64{
+			"node1": {10, 10},
+			"node2": {8, 10},
+		}))
+		assert.Empty(t, missed)
+
+		// Single validator missing blocks gets the exact heights
+		f.pendingHeights = []uint64{1042}
+		missed, _, unattributed := f.attribute(newValidatorsResponse(1000, map[string][2]int64{
+			"node1": {11, 12},
+			"node2": {9, 11},
+		}))
+		require.Len(t, missed, 1)
+		assert.Equal(t, missedBlock{AccountID: "node1", Count: 1, Heights: []uint64{1042}}, missed[0])
+		assert.Empty(t, unattributed)
+		assert.Empty(t, f.pendingHeights)
+
+		// Counters lagging behind the walked heights keep heights pending
+		f.pendingHeights = []uint64{1050, 1051}
+		missed, _, _ = f.attribute(newValidatorsResponse(1000, map[string][2]int64{
+			"node1": {11, 12},
+			"node2": {9, 12},
+		}))
+		require.Len(t, missed, 1)
+		assert.Equal(t, missedBlock{AccountID: "node2", Count: 1, Heights: []uint64{1050}}, missed[0])
+		assert.Equal(t, []uint64{1051}, f.pendingHeights)
+
+		// Several validators missing blocks leave the heights unattributed
+		f.pendingHeights = append(f.pendingHeights, 1060)
+		missed, _, unattributed = f.attribute(newValidatorsResponse(1000, map[string][2]int64{
+			"node1": {11, 13},
+			"node2": {9, 13},
+		}))
+		assert.Equal(t, []missedBlock{
+			{AccountID: "node1", Count: 1},
+			{AccountID: "node2", Count: 1},
+		}, missed)
+		assert.Equal(t, []uint64{1051, 1060}, unattributed)
+		assert.Empty(t, f.pendingHeights)
+
+		// Counters are reset on epoch change
+		missed, _, _ = f.attribute(newValidatorsResponse(2000, map[string][2]int64{
+			"node1": {0, 1},
+			"node2": {0, 0},
+		}))
+		assert.Empty(t, missed)
+		assert.Empty(t, f.pendingHeights)
+	})
+	t.Run("Attribute missed chunks", func(t *testing.T) {
+		f := blockFollower{}
+
+		validators := func(produced, expected []int64) near.ValidatorsResponse {
+			v := near.CurrentEpochValidatorInfo{}
+			v.AccountId = "node1"
+			v.Shards = []int{2, 3}
+			v.NumProducedChunksPerShard = produced
+			v.NumExpectedChunksPerShard = expected
+			return near.ValidatorsResponse{
+				EpochStartHeight:  1000,
+				CurrentValidators: []near.CurrentEpochValidatorInfo{v},
+			}
+		}
+
+		_, missed, _ := f.attribute(validators([]int64{10, 10}, []int64{10, 10}))
+		assert.Empty(t, missed)
+
+		_, missed, _ = f.attribute(validators([]int64{12, 10}, []int64{12, 13}))
+		assert.Equal(t, []missedChunk{{AccountID: "node1", ShardID: 3, Count: 3}}, missed)
+	})
+}
+
+func TestBlockChunks(t *testing.T) {
+	var block near.BlockResponse
+	err := json.Unmarshal([]byte(`
+		{
+			"author": "node1",
+			"header": {"height": 100, "chunk_mask": [true, false, true]},
+			"chunks": [
+				{"shard_id": 0, "height_created": 100, "height_included": 100},
+				{"shard_id": 1, "height_created": 98, "height_included": 98},
+				{"shard_id": 3, "height_created": 100, "height_included": 100}
+			]
+		}`), &block)
+	require.NoError(t, err)
+
+	assert.Equal(t, []shardChunk{
+		{ShardID: 0, Included: true},
+		{ShardID: 1, Included: false},
+		{ShardID: 3, Included: true},
+	}, blockChunks(block))
+
+	// Without chunk mask, inclusion height is used
+	block.Header.ChunkMask = nil
+	block.Chunks[2].HeightIncluded = 99
+	assert.Equal(t, []shardChunk{
+		{ShardID: 0, Included: true},
+		{ShardID: 1, Included: false},
+		{ShardID: 3, Included: false},
+	}, blockChunks(block))
+}
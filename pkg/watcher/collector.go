@@ -0,0 +1,70 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/sirupsen/logrus"
+)
+
+// Collector names, used as collector label of the collection metrics.
+const (
+	collectorBlocks         = "blocks"
+	collectorEpochEnd       = "epoch_end"
+	collectorNodeKey        = "node_key"
+	collectorProtocolConfig = "protocol_config"
+	collectorReferences     = "references"
+	collectorRewards        = "rewards"
+	collectorStakingPools   = "staking_pools"
+	collectorStatus         = "status"
+	collectorValidators     = "validators"
+)
+
+// collect runs a collector with its own timeout and retries, so that its
+// failure doesn't affect the other collectors.
+func (w *Watcher) collect(ctx context.Context, name string, collector func(ctx context.Context) error) error {
+	timeout := w.config.CollectTimeout
+	if timeout <= 0 {
+		timeout = w.config.RefreshRate
+	}
+	collectCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	logger := logrus.WithFields(logrus.Fields{
+		"network":   w.config.Network,
+		"collector": name,
+	})
+
+	var lastErr error
+	err := retry.Do(func() error {
+		lastErr = collector(collectCtx)
+		return lastErr
+	},
+		retry.Context(collectCtx),
+		retry.Delay(1*time.Second),
+		retry.Attempts(3),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(near.IsRetryable),
+		retry.OnRetry(func(n uint, err error) {
+			logger.WithError(err).Warn("failed to collect data, retrying...")
+		}),
+	)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		// The timeout may expire while waiting to retry, hiding the actual error
+		if errors.Is(err, context.DeadlineExceeded) && lastErr != nil {
+			err = lastErr
+		}
+		w.metrics.CollectionErrors.WithLabelValues(name).Inc()
+		logger.WithError(err).Error("failed to collect data")
+		return err
+	}
+
+	w.metrics.LastSuccessTimestamp.WithLabelValues(name).SetToCurrentTime()
+	return nil
+}
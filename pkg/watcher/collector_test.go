@@ -0,0 +1,36 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollect(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		metrics = metrics.New("near_validator_watcher")
+		watcher = New(nil, metrics, &Config{RefreshRate: time.Minute, CollectTimeout: 100 * time.Millisecond})
+	)
+
+	// Retried until the collector timeout
+	err := watcher.collect(ctx, collectorStatus, func(ctx context.Context) error {
+		return errors.New("boom")
+	})
+	assert.EqualError(t, err, "boom")
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.CollectionErrors.WithLabelValues(collectorStatus)))
+	assert.Equal(t, 0, testutil.CollectAndCount(metrics.LastSuccessTimestamp))
+
+	err = watcher.collect(ctx, collectorValidators, func(ctx context.Context) error {
+		_, ok := ctx.Deadline()
+		assert.True(t, ok)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.InDelta(t, float64(time.Now().Unix()), testutil.ToFloat64(metrics.LastSuccessTimestamp.WithLabelValues(collectorValidators)), 5)
+}
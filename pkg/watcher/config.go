@@ -3,10 +3,90 @@ package watcher
 import (
 	"io"
 	"time"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/alert"
+	"github.com/kilnfi/near-validator-watcher/pkg/history"
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/kilnfi/near-validator-watcher/pkg/report"
 )
 
 type Config struct {
+	// Network names the watched network, it is added to alerts when set
+	Network string
+
 	TrackedAccounts []string
 	RefreshRate     time.Duration
 	Writer          io.Writer
+
+	// CollectTimeout bounds each collector, retries included. Defaults to the
+	// refresh rate
+	CollectTimeout time.Duration
+
+	// Health configures the readiness and liveness checks
+	Health HealthThresholds
+
+	// References are optional RPC nodes the watched node is compared to, by
+	// name
+	References map[string]*near.Client
+
+	// Validators holds optional settings of tracked accounts
+	Validators map[string]ValidatorConfig
+
+	// History is optional, epochs are recorded when set
+	History *history.Store
+
+	// Reports is optional, reports of tracked validators are written at the
+	// end of each epoch when set
+	Reports *report.Writer
+
+	// Alerts is optional, no alert is raised when nil
+	Alerts               *alert.Manager
+	AlertUptimeThreshold float64
+}
+
+type ValidatorConfig struct {
+	// Name is displayed instead of the account id
+	Name string
+	// UptimeThreshold overrides Config.AlertUptimeThreshold
+	UptimeThreshold float64
+	// Labels are added to the alerts of the validator
+	Labels map[string]string
+}
+
+// displayName returns the configured name of the account, or its shortened id.
+func (c *Config) displayName(accountID string) string {
+	if v, ok := c.Validators[accountID]; ok && v.Name != "" {
+		return v.Name
+	}
+	return prettyPrintAccountID(accountID)
+}
+
+// uptimeThreshold returns the alert uptime threshold of the account.
+func (c *Config) uptimeThreshold(accountID string) float64 {
+	if v, ok := c.Validators[accountID]; ok && v.UptimeThreshold != 0 {
+		return v.UptimeThreshold
+	}
+	if c.AlertUptimeThreshold != 0 {
+		return c.AlertUptimeThreshold
+	}
+	return defaultAlertUptimeThreshold
+}
+
+// alertLabels returns the labels of the alerts raised for the account, or
+// for the network when accountID is empty.
+func (c *Config) alertLabels(accountID string) map[string]string {
+	labels := make(map[string]string)
+	for k, v := range c.Validators[accountID].Labels {
+		labels[k] = v
+	}
+	if accountID != "" {
+		labels["account_id"] = accountID
+	}
+	if c.Network != "" {
+		labels["network"] = c.Network
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+	return labels
 }
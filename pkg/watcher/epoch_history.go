@@ -0,0 +1,151 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/history"
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/kilnfi/near-validator-watcher/pkg/report"
+	"github.com/sirupsen/logrus"
+)
+
+// maxSkippedEpochEndBlocks is how many heights are tried before the start of
+// an epoch to find the last block of the previous one.
+const maxSkippedEpochEndBlocks = 5
+
+type epochSnapshot struct {
+	validators near.ValidatorsResponse
+	height     int64
+}
+
+// recordEpochEnd records the final stats of the previous epoch and writes the
+// reports of the tracked validators when a new epoch starts, or at startup if
+// it hasn't been done yet. The snapshot of the previous epoch is kept until
+// they are written, so that a failure is retried at the next cycle.
+func (w *Watcher) recordEpochEnd(ctx context.Context, status near.StatusResponse, validators near.ValidatorsResponse) error {
+	if w.config.History == nil && w.config.Reports == nil {
+		return nil
+	}
+
+	var (
+		previous = w.lastEpochSnapshot
+		current  = &epochSnapshot{validators: validators, height: int64(status.SyncInfo.LatestBlockHeight)}
+	)
+	if previous != nil && previous.validators.EpochStartHeight == validators.EpochStartHeight {
+		w.lastEpochSnapshot = current
+		return nil
+	}
+
+	epochHeight := validators.EpochHeight - 1
+	if epochHeight < 0 {
+		w.lastEpochSnapshot = current
+		return nil
+	}
+
+	record := w.config.History != nil && !w.config.History.Has(epochHeight)
+	reports := make([]string, 0)
+	if w.config.Reports != nil {
+		for _, account := range w.config.TrackedAccounts {
+			if !w.config.Reports.Has(epochHeight, account) {
+				reports = append(reports, account)
+			}
+		}
+	}
+	if !record && len(reports) == 0 {
+		w.lastEpochSnapshot = current
+		return nil
+	}
+
+	epoch, err := w.fetchFinalEpoch(ctx, validators.EpochStartHeight)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil && near.IsRetryable(err) {
+		return fmt.Errorf("failed to get final stats of epoch %d: %w", epochHeight, err)
+	}
+	if err != nil || epoch.EpochHeight != epochHeight {
+		if previous == nil || previous.validators.EpochHeight != epochHeight {
+			logrus.WithError(err).WithField("epoch_height", epochHeight).Warn("failed to get final stats of the previous epoch")
+			w.lastEpochSnapshot = current
+			return nil
+		}
+
+		// The last stats collected may miss the very last blocks of the epoch
+		logrus.WithError(err).WithField("epoch_height", epochHeight).Warn("failed to get final stats of the previous epoch, using last collected ones")
+		epoch = history.Epoch{
+			EpochHeight:      previous.validators.EpochHeight,
+			EpochStartHeight: previous.validators.EpochStartHeight,
+			LastBlockHeight:  previous.height,
+			Validators:       previous.validators.CurrentValidators,
+		}
+	}
+	epoch.RecordedAt = time.Now().UTC()
+
+	errs := make([]error, 0)
+	if record {
+		errs = append(errs, w.saveEpochHistory(epoch))
+	}
+	for _, account := range reports {
+		errs = append(errs, w.writeEpochReport(epoch, validators, account))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	w.lastEpochSnapshot = current
+	return nil
+}
+
+func (w *Watcher) saveEpochHistory(epoch history.Epoch) error {
+	if err := w.config.History.Save(epoch); err != nil {
+		return fmt.Errorf("failed to record history of epoch %d: %w", epoch.EpochHeight, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"epoch_height":      epoch.EpochHeight,
+		"last_block_height": epoch.LastBlockHeight,
+		"validators":        len(epoch.Validators),
+	}).Info("recorded epoch history")
+	return nil
+}
+
+func (w *Watcher) writeEpochReport(epoch history.Epoch, next near.ValidatorsResponse, accountID string) error {
+	if err := w.config.Reports.Write(report.New(w.config.Network, epoch, next, accountID)); err != nil {
+		return fmt.Errorf("failed to write report of %s for epoch %d: %w", accountID, epoch.EpochHeight, err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"epoch_height": epoch.EpochHeight,
+		"account_id":   accountID,
+	}).Info("wrote epoch report")
+	return nil
+}
+
+// fetchFinalEpoch returns the validators stats at the last block before the
+// given epoch start height.
+func (w *Watcher) fetchFinalEpoch(ctx context.Context, epochStartHeight int64) (history.Epoch, error) {
+	var err error
+
+	for height := epochStartHeight - 1; height >= epochStartHeight-maxSkippedEpochEndBlocks && height > 0; height-- {
+		var validators near.ValidatorsResponse
+		validators, err = w.client.Validators(ctx, map[string]interface{}{"block_id": height})
+		if errors.Is(err, near.ErrUnknownBlock) {
+			continue
+		}
+		if err != nil {
+			return history.Epoch{}, err
+		}
+
+		return history.Epoch{
+			EpochHeight:      validators.EpochHeight,
+			EpochStartHeight: validators.EpochStartHeight,
+			LastBlockHeight:  height,
+			Validators:       validators.CurrentValidators,
+		}, nil
+	}
+
+	return history.Epoch{}, err
+}
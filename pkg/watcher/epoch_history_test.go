@@ -0,0 +1,66 @@
+package watcher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/history"
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/kilnfi/near-validator-watcher/pkg/near/testutils"
+	"github.com/kilnfi/near-validator-watcher/pkg/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordEpochEnd(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		resp   = testutils.ExpectedResponse{}
+		server = testutils.NewServer(&resp)
+	)
+	defer server.Close()
+
+	store, err := history.Open(t.TempDir())
+	require.NoError(t, err)
+
+	reports, err := report.NewWriter(t.TempDir())
+	require.NoError(t, err)
+
+	watcher := New(near.NewClient([]string{server.URL}), nil, &Config{
+		TrackedAccounts: []string{"kiln.poolv1.near"},
+		History:         store,
+		Reports:         reports,
+	})
+
+	var status near.StatusResponse
+	status.SyncInfo.LatestBlockHeight = 250
+
+	// Failing to get the final stats is retried at the next cycle
+	resp.ExpectResponse(503, `service unavailable`)
+	require.Error(t, watcher.recordEpochEnd(ctx, status, near.ValidatorsResponse{EpochHeight: 2001, EpochStartHeight: 200}))
+	assert.False(t, store.Has(2000))
+	assert.False(t, reports.Has(2000, "kiln.poolv1.near"))
+
+	// Final stats of the previous epoch, at its last block
+	resp.ExpectResponse(200, `{"jsonrpc": "2.0", "id": "dontcare", "result": {
+		"epoch_height": 2000,
+		"epoch_start_height": 100,
+		"current_validators": [{"account_id": "kiln.poolv1.near", "num_produced_blocks": 99, "num_expected_blocks": 100}]
+	}}`)
+
+	require.NoError(t, watcher.recordEpochEnd(ctx, status, near.ValidatorsResponse{EpochHeight: 2001, EpochStartHeight: 200}))
+
+	epoch, err := store.Get(2000)
+	require.NoError(t, err)
+	assert.Equal(t, int64(199), epoch.LastBlockHeight)
+	v, ok := epoch.Validator("kiln.poolv1.near")
+	require.True(t, ok)
+	assert.Equal(t, int64(99), v.NumProducedBlocks)
+	assert.True(t, reports.Has(2000, "kiln.poolv1.near"))
+
+	// Nothing recorded while the epoch doesn't change
+	require.NoError(t, watcher.recordEpochEnd(ctx, status, near.ValidatorsResponse{EpochHeight: 2001, EpochStartHeight: 200}))
+	heights, err := store.Heights()
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2000}, heights)
+}
@@ -0,0 +1,164 @@
+package watcher
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+)
+
+// HealthThresholds configures the health checks, a zero value disables the
+// corresponding check.
+type HealthThresholds struct {
+	// MaxCollectionAge is the longest time without successful collection
+	// before the watcher isn't ready
+	MaxCollectionAge time.Duration
+	// MaxBlockAge is the oldest the latest block of the node can be before the
+	// watcher isn't ready
+	MaxBlockAge time.Duration
+	// MaxFailures is the number of consecutive failed collections from which
+	// the watcher isn't ready
+	MaxFailures int
+	// MaxCycleAge is the longest time without completing a collection cycle,
+	// successful or not, before the watcher isn't live
+	MaxCycleAge time.Duration
+}
+
+// Check is the result of a single health check.
+type Check struct {
+	Name    string `json:"name"`
+	OK      bool   `json:"ok"`
+	Message string `json:"message,omitempty"`
+}
+
+// Health holds the result of the checks of a probe, it is healthy when every
+// check is.
+type Health struct {
+	Healthy bool    `json:"healthy"`
+	Checks  []Check `json:"checks"`
+}
+
+func newHealth(checks ...Check) Health {
+	health := Health{Healthy: true, Checks: checks}
+	for _, check := range checks {
+		health.Healthy = health.Healthy && check.OK
+	}
+	return health
+}
+
+// healthState is updated by the watcher loop and read by the probes.
+type healthState struct {
+	mu              sync.Mutex
+	startedAt       time.Time
+	lastCycle       time.Time
+	lastSuccess     time.Time
+	latestBlockTime time.Time
+	syncing         bool
+	failures        int
+}
+
+// recordCollection records the outcome of the status and validators
+// collection of a cycle.
+func (w *Watcher) recordCollection(status near.StatusResponse, err error) {
+	w.health.mu.Lock()
+	defer w.health.mu.Unlock()
+
+	if err != nil {
+		w.health.failures++
+		return
+	}
+
+	w.health.failures = 0
+	w.health.lastSuccess = time.Now()
+	w.health.syncing = status.SyncInfo.Syncing
+	if t, err := time.Parse(time.RFC3339Nano, status.SyncInfo.LatestBlockTime); err == nil {
+		w.health.latestBlockTime = t
+	}
+}
+
+func (w *Watcher) recordCycle() {
+	w.health.mu.Lock()
+	defer w.health.mu.Unlock()
+
+	w.health.lastCycle = time.Now()
+}
+
+// Readiness checks that data is collected successfully and that the node is
+// synced with a recent latest block.
+func (w *Watcher) Readiness() Health {
+	w.health.mu.Lock()
+	defer w.health.mu.Unlock()
+
+	var (
+		now        = time.Now()
+		thresholds = w.config.Health
+		checks     = make([]Check, 0, 4)
+	)
+
+	if w.health.lastSuccess.IsZero() {
+		checks = append(checks, Check{Name: "collection", Message: "no successful collection yet"})
+		return newHealth(checks...)
+	}
+
+	synced := Check{Name: "synced", OK: !w.health.syncing}
+	if w.health.syncing {
+		synced.Message = "node is syncing"
+	}
+	checks = append(checks, synced)
+
+	if thresholds.MaxCollectionAge > 0 {
+		age := now.Sub(w.health.lastSuccess)
+		check := Check{Name: "collection_age", OK: age <= thresholds.MaxCollectionAge}
+		if !check.OK {
+			check.Message = fmt.Sprintf("last successful collection %s ago, more than %s", age.Round(time.Second), thresholds.MaxCollectionAge)
+		}
+		checks = append(checks, check)
+	}
+
+	if thresholds.MaxBlockAge > 0 {
+		age := now.Sub(w.health.latestBlockTime)
+		check := Check{Name: "block_age", OK: age <= thresholds.MaxBlockAge}
+		switch {
+		case w.health.latestBlockTime.IsZero():
+			check.OK = false
+			check.Message = "latest block time unknown"
+		case !check.OK:
+			check.Message = fmt.Sprintf("latest block is %s old, more than %s", age.Round(time.Second), thresholds.MaxBlockAge)
+		}
+		checks = append(checks, check)
+	}
+
+	if thresholds.MaxFailures > 0 {
+		check := Check{Name: "failures", OK: w.health.failures < thresholds.MaxFailures}
+		if !check.OK {
+			check.Message = fmt.Sprintf("%d consecutive failed collections", w.health.failures)
+		}
+		checks = append(checks, check)
+	}
+
+	return newHealth(checks...)
+}
+
+// Liveness checks that the watcher loop keeps completing collection cycles,
+// whatever their outcome.
+func (w *Watcher) Liveness() Health {
+	w.health.mu.Lock()
+	defer w.health.mu.Unlock()
+
+	if w.config.Health.MaxCycleAge <= 0 {
+		return newHealth()
+	}
+
+	last := w.health.lastCycle
+	if last.IsZero() {
+		last = w.health.startedAt
+	}
+	age := time.Since(last)
+
+	check := Check{Name: "cycle_age", OK: last.IsZero() || age <= w.config.Health.MaxCycleAge}
+	if !check.OK {
+		check.Message = fmt.Sprintf("last collection cycle %s ago, more than %s", age.Round(time.Second), w.config.Health.MaxCycleAge)
+	}
+	return newHealth(check)
+}
@@ -0,0 +1,59 @@
+package watcher
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealth(t *testing.T) {
+	watcher := New(nil, nil, &Config{
+		Health: HealthThresholds{
+			MaxCollectionAge: time.Minute,
+			MaxBlockAge:      time.Minute,
+			MaxFailures:      2,
+			MaxCycleAge:      time.Minute,
+		},
+	})
+
+	health := watcher.Readiness()
+	assert.False(t, health.Healthy)
+	assert.Equal(t, []Check{{Name: "collection", Message: "no successful collection yet"}}, health.Checks)
+	assert.True(t, watcher.Liveness().Healthy)
+
+	// Latest block time not reported by the node
+	var status near.StatusResponse
+	watcher.recordCollection(status, nil)
+	health = watcher.Readiness()
+	assert.False(t, health.Healthy)
+	assert.Equal(t, Check{Name: "block_age", Message: "latest block time unknown"}, health.Checks[2])
+
+	status.SyncInfo.LatestBlockTime = time.Now().Add(-10 * time.Second).Format(time.RFC3339Nano)
+	watcher.recordCollection(status, nil)
+	watcher.recordCycle()
+	assert.True(t, watcher.Readiness().Healthy)
+
+	// A single failure is tolerated, not two
+	watcher.recordCollection(status, errors.New("boom"))
+	assert.True(t, watcher.Readiness().Healthy)
+	watcher.recordCollection(status, errors.New("boom"))
+	health = watcher.Readiness()
+	assert.False(t, health.Healthy)
+	require.Len(t, health.Checks, 4)
+	assert.Equal(t, Check{Name: "failures", Message: "2 consecutive failed collections"}, health.Checks[3])
+
+	// Stale latest block
+	status.SyncInfo.LatestBlockTime = time.Now().Add(-5 * time.Minute).Format(time.RFC3339Nano)
+	watcher.recordCollection(status, nil)
+	health = watcher.Readiness()
+	assert.False(t, health.Healthy)
+	assert.False(t, health.Checks[2].OK)
+	assert.Contains(t, health.Checks[2].Message, "latest block is 5m0s old")
+
+	watcher.health.lastCycle = time.Now().Add(-2 * time.Minute)
+	assert.False(t, watcher.Liveness().Healthy)
+}
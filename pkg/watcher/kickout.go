@@ -0,0 +1,97 @@
+package watcher
+
+import (
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+)
+
+// defaultKickoutThreshold is used until the protocol config has been fetched.
+const defaultKickoutThreshold = 90
+
+// kickoutForecast tells how close a validator is to be kicked out at the end of
+// the epoch, for either blocks or chunks.
+type kickoutForecast struct {
+	// ExpectedRemaining is the estimated number of blocks (or chunks) the
+	// validator is still expected to produce before the end of the epoch.
+	ExpectedRemaining int64
+	// TolerableMisses is how many more blocks (or chunks) can be missed before
+	// the validator falls below the kickout threshold. It is negative when the
+	// validator will be kicked out even without missing anything else.
+	TolerableMisses int64
+	// ProjectedUptime is the uptime at the end of the epoch (in percent) if
+	// the validator produces every remaining expected block (or chunk).
+	ProjectedUptime float64
+}
+
+// kickoutThresholds returns the blocks and chunks kickout thresholds in percent.
+func kickoutThresholds(config near.ProtocolConfigResponse) (int, int) {
+	var (
+		blocks = config.BlockProducerKickoutThreshold
+		chunks = config.ChunkProducerKickoutThreshold
+	)
+	if blocks == 0 {
+		blocks = defaultKickoutThreshold
+	}
+	if chunks == 0 {
+		chunks = defaultKickoutThreshold
+	}
+	return blocks, chunks
+}
+
+// endorsementsKickoutThreshold returns the endorsements kickout threshold of
+// chunk validators only in percent.
+func endorsementsKickoutThreshold(config near.ProtocolConfigResponse) int {
+	if config.ChunkValidatorOnlyKickoutThreshold == 0 {
+		return defaultKickoutThreshold
+	}
+	return config.ChunkValidatorOnlyKickoutThreshold
+}
+
+// epochProgress returns the number of heights elapsed and remaining in the epoch.
+func epochProgress(validators near.ValidatorsResponse, config near.ProtocolConfigResponse, latestHeight uint64) (int64, int64) {
+	var (
+		elapsed   = int64(latestHeight) - validators.EpochStartHeight
+		remaining = validators.EpochStartHeight + int64(config.EpochLength) - int64(latestHeight)
+	)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+	return elapsed, remaining
+}
+
+// forecastKickout estimates the amount of blocks (or chunks) the validator is
+// still expected to produce based on its rate since the beginning of the epoch.
+//
+// A validator is kicked out when produced * 100 < threshold * expected.
+func forecastKickout(produced, expected, elapsed, remaining int64, threshold int) kickoutForecast {
+	var expectedRemaining int64
+	if elapsed > 0 {
+		expectedRemaining = expected * remaining / elapsed
+	}
+
+	var (
+		finalExpected = expected + expectedRemaining
+		finalProduced = produced + expectedRemaining
+		forecast      = kickoutForecast{
+			ExpectedRemaining: expectedRemaining,
+			ProjectedUptime:   100,
+		}
+	)
+
+	if finalExpected > 0 {
+		forecast.ProjectedUptime = 100 * float64(finalProduced) / float64(finalExpected)
+		forecast.TolerableMisses = floorDiv(100*finalProduced-int64(threshold)*finalExpected, 100)
+	}
+
+	return forecast
+}
+
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
@@ -0,0 +1,58 @@
+package watcher
+
+import (
+	"testing"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEpochProgress(t *testing.T) {
+	validators := near.ValidatorsResponse{EpochStartHeight: 1000}
+	config := near.ProtocolConfigResponse{EpochLength: 100}
+
+	elapsed, remaining := epochProgress(validators, config, 1025)
+	assert.Equal(t, int64(25), elapsed)
+	assert.Equal(t, int64(75), remaining)
+
+	elapsed, remaining = epochProgress(validators, config, 1200)
+	assert.Equal(t, int64(200), elapsed)
+	assert.Equal(t, int64(0), remaining)
+}
+
+func TestForecastKickout(t *testing.T) {
+	// Half way through the epoch with 10 missed blocks out of 100
+	forecast := forecastKickout(90, 100, 500, 500, 80)
+	assert.Equal(t, int64(100), forecast.ExpectedRemaining)
+	assert.Equal(t, float64(95), forecast.ProjectedUptime)
+	// (90 + 100 - m) * 100 >= 80 * 200
+	assert.Equal(t, int64(30), forecast.TolerableMisses)
+
+	// Already too many misses to recover
+	forecast = forecastKickout(10, 100, 500, 500, 80)
+	assert.Equal(t, float64(55), forecast.ProjectedUptime)
+	assert.Equal(t, int64(-50), forecast.TolerableMisses)
+
+	// End of epoch
+	forecast = forecastKickout(81, 100, 1000, 0, 80)
+	assert.Equal(t, int64(0), forecast.ExpectedRemaining)
+	assert.Equal(t, int64(1), forecast.TolerableMisses)
+
+	// Not expected to produce anything
+	forecast = forecastKickout(0, 0, 500, 500, 80)
+	assert.Equal(t, float64(100), forecast.ProjectedUptime)
+	assert.Equal(t, int64(0), forecast.TolerableMisses)
+}
+
+func TestKickoutThresholds(t *testing.T) {
+	blocks, chunks := kickoutThresholds(near.ProtocolConfigResponse{})
+	assert.Equal(t, defaultKickoutThreshold, blocks)
+	assert.Equal(t, defaultKickoutThreshold, chunks)
+
+	blocks, chunks = kickoutThresholds(near.ProtocolConfigResponse{
+		BlockProducerKickoutThreshold: 80,
+		ChunkProducerKickoutThreshold: 70,
+	})
+	assert.Equal(t, 80, blocks)
+	assert.Equal(t, 70, chunks)
+}
@@ -0,0 +1,65 @@
+package watcher
+
+import (
+	"github.com/kilnfi/near-validator-watcher/pkg/metrics"
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// collectNextEpoch compares the current epoch with the next one for the
+// tracked validators: membership, pending proposal and stake change.
+func (w *Watcher) collectNextEpoch(validators near.ValidatorsResponse) {
+	logrus.Debug("collect next epoch")
+
+	w.metrics.ValidatorInNextEpoch.Reset()
+	w.metrics.ValidatorNextStakeDelta.Reset()
+	w.metrics.ValidatorProposalPending.Reset()
+
+	if w.inNextEpoch == nil {
+		w.inNextEpoch = make(map[string]bool)
+	}
+
+	for _, account := range w.config.TrackedAccounts {
+		var (
+			currentStake = decimal.Zero
+			nextStake    = decimal.Zero
+			inNext       = false
+			proposed     = false
+		)
+		for _, v := range validators.CurrentValidators {
+			if v.AccountId == account {
+				currentStake = v.Stake
+			}
+		}
+		for _, v := range validators.NextValidators {
+			if v.AccountId == account {
+				nextStake = v.Stake
+				inNext = true
+			}
+		}
+		for _, v := range validators.CurrentProposals {
+			if v.AccountId == account {
+				proposed = true
+			}
+		}
+
+		// Only log when the membership changes
+		previous, known := w.inNextEpoch[account]
+		if !inNext && (!known || previous) {
+			logrus.WithFields(logrus.Fields{
+				"account_id":       account,
+				"epoch_height":     validators.EpochHeight,
+				"pending_proposal": proposed,
+			}).Warn("tracked validator is not part of the next epoch validators")
+		}
+		if inNext && known && !previous {
+			logrus.WithField("account_id", account).Info("tracked validator is part of the next epoch validators again")
+		}
+		w.inNextEpoch[account] = inNext
+
+		w.metrics.ValidatorInNextEpoch.WithLabelValues(account).Set(metrics.BoolToFloat64(inNext))
+		w.metrics.ValidatorNextStakeDelta.WithLabelValues(account).Set(nextStake.Sub(currentStake).Div(yoctoUnit).InexactFloat64())
+		w.metrics.ValidatorProposalPending.WithLabelValues(account).Set(metrics.BoolToFloat64(proposed))
+	}
+}
@@ -0,0 +1,64 @@
+package watcher
+
+import (
+	"context"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/metrics"
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/sirupsen/logrus"
+)
+
+// collectNodeKey compares the key the node validates with to the keys
+// registered for its account: a node running with another key won't produce.
+func (w *Watcher) collectNodeKey(ctx context.Context, status near.StatusResponse, validators near.ValidatorsResponse) error {
+	logrus.Debug("collect node key")
+
+	w.metrics.NodeValidatorKeyMismatch.Reset()
+
+	account := status.ValidatorAccountID
+	if account == "" {
+		return nil
+	}
+
+	keys := make(map[string]string)
+	for _, v := range validators.CurrentValidators {
+		if v.AccountId == account {
+			keys["current_validators"] = v.PublicKey
+		}
+	}
+	for _, v := range validators.NextValidators {
+		if v.AccountId == account {
+			keys["next_validators"] = v.PublicKey
+		}
+	}
+
+	if pool, ok := w.stakingPools[account]; ok {
+		keys["staking_pool"] = pool.StakingKey
+	} else {
+		var stakingKey string
+		err := w.client.ViewFunction(ctx, account, "get_staking_key", &stakingKey)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			logrus.WithError(err).WithField("account_id", account).Warn("failed to get staking key")
+		} else {
+			keys["staking_pool"] = stakingKey
+		}
+	}
+
+	for source, key := range keys {
+		mismatch := key != status.ValidatorPublicKey
+		if mismatch {
+			logrus.WithFields(logrus.Fields{
+				"account_id": account,
+				"source":     source,
+				"node_key":   status.ValidatorPublicKey,
+				"key":        key,
+			}).Error("node is running with a validator key that won't be producing")
+		}
+		w.metrics.NodeValidatorKeyMismatch.WithLabelValues(account, source).Set(metrics.BoolToFloat64(mismatch))
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/metrics"
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectNodeKey(t *testing.T) {
+	var (
+		metrics    = metrics.New("near_validator_watcher")
+		watcher    = New(nil, metrics, &Config{})
+		status     near.StatusResponse
+		validators near.ValidatorsResponse
+	)
+
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"validator_account_id": "kiln.pool.f863973.m0",
+		"validator_public_key": "ed25519:Bq8fe1eUgDRexX2CYDMhMMQBiN13j8vTAVFyTNhEfh1W"
+	}`), &status))
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"current_validators": [{"account_id": "kiln.pool.f863973.m0", "public_key": "ed25519:Bq8fe1eUgDRexX2CYDMhMMQBiN13j8vTAVFyTNhEfh1W"}],
+		"next_validators": [{"account_id": "kiln.pool.f863973.m0", "public_key": "ed25519:9NBTDmtpY4ALxD2WRqbNC2FX5ATcGdbk8tLWKBbVJoRX"}]
+	}`), &validators))
+	watcher.stakingPools = map[string]near.StakingPool{
+		"kiln.pool.f863973.m0": {StakingKey: "ed25519:9NBTDmtpY4ALxD2WRqbNC2FX5ATcGdbk8tLWKBbVJoRX"},
+	}
+
+	require.NoError(t, watcher.collectNodeKey(context.Background(), status, validators))
+
+	assert.Equal(t, float64(0), testutil.ToFloat64(metrics.NodeValidatorKeyMismatch.WithLabelValues("kiln.pool.f863973.m0", "current_validators")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.NodeValidatorKeyMismatch.WithLabelValues("kiln.pool.f863973.m0", "next_validators")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.NodeValidatorKeyMismatch.WithLabelValues("kiln.pool.f863973.m0", "staking_pool")))
+
+	// Nothing to compare when the node isn't a validator
+	require.NoError(t, watcher.collectNodeKey(context.Background(), near.StatusResponse{}, validators))
+	assert.Equal(t, 0, testutil.CollectAndCount(metrics.NodeValidatorKeyMismatch))
+}
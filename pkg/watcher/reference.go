@@ -0,0 +1,136 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/metrics"
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/sirupsen/logrus"
+)
+
+// collectReferences compares the latest block of the watched node with the one
+// of every reference node to measure lags. Final blocks are compared at the
+// same height to detect a fork, using the final height of the node which is
+// behind, so that optimistic blocks which get reorganized aren't reported.
+func (w *Watcher) collectReferences(ctx context.Context, status near.StatusResponse) error {
+	logrus.Debug("collect reference nodes")
+
+	final, err := w.client.BlockByFinality(ctx, "final")
+	if err != nil {
+		return fmt.Errorf("failed to get final block: %w", err)
+	}
+
+	names := make([]string, 0, len(w.config.References))
+	for name := range w.config.References {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	errs := make([]error, 0)
+	for _, name := range names {
+		err := w.compareReference(ctx, name, w.config.References[name], status, final)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			w.metrics.ReferenceNodeFork.DeleteLabelValues(name)
+			w.metrics.ReferenceNodeHeightLag.DeleteLabelValues(name)
+			w.metrics.ReferenceNodeTimeLag.DeleteLabelValues(name)
+			errs = append(errs, fmt.Errorf("reference node %s: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (w *Watcher) compareReference(ctx context.Context, name string, reference *near.Client, status near.StatusResponse, final near.BlockResponse) error {
+	refStatus, err := reference.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	heightLag := int64(refStatus.SyncInfo.LatestBlockHeight) - int64(status.SyncInfo.LatestBlockHeight)
+	w.metrics.ReferenceNodeHeightLag.WithLabelValues(name).Set(float64(heightLag))
+
+	timeLag, err := blockTimeLag(status.SyncInfo.LatestBlockTime, refStatus.SyncInfo.LatestBlockTime)
+	if err != nil {
+		return err
+	}
+	w.metrics.ReferenceNodeTimeLag.WithLabelValues(name).Set(timeLag.Seconds())
+
+	refFinal, err := reference.BlockByFinality(ctx, "final")
+	if err != nil {
+		return fmt.Errorf("failed to get final block: %w", err)
+	}
+
+	// Get the final block of the node which is ahead at the final height of
+	// the other one
+	var (
+		height    = uint64(final.Header.Height)
+		refHeight = uint64(refFinal.Header.Height)
+		hash      = final.Header.Hash
+		refHash   = refFinal.Header.Hash
+	)
+	switch {
+	case refHeight > height:
+		block, err := reference.Block(ctx, height)
+		if errors.Is(err, near.ErrUnknownBlock) {
+			// The block was garbage collected, forks can't be detected until
+			// the nodes are closer
+			w.metrics.ReferenceNodeFork.DeleteLabelValues(name)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		refHash = block.Header.Hash
+	case refHeight < height:
+		block, err := w.client.Block(ctx, refHeight)
+		if errors.Is(err, near.ErrUnknownBlock) {
+			w.metrics.ReferenceNodeFork.DeleteLabelValues(name)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		hash = block.Header.Hash
+		height = refHeight
+	}
+
+	fork := hash != refHash
+	if fork {
+		logrus.WithFields(logrus.Fields{
+			"reference":      name,
+			"height":         height,
+			"hash":           hash,
+			"reference_hash": refHash,
+		}).Error("watched node diverges from the reference node")
+	}
+	w.metrics.ReferenceNodeFork.WithLabelValues(name).Set(metrics.BoolToFloat64(fork))
+
+	logrus.WithFields(logrus.Fields{
+		"reference":  name,
+		"height_lag": heightLag,
+		"time_lag":   timeLag,
+	}).Debug("compared reference node")
+
+	return nil
+}
+
+// blockTimeLag returns how long the reference latest block time is ahead of
+// the watched one.
+func blockTimeLag(latest string, refLatest string) (time.Duration, error) {
+	t, err := time.Parse(time.RFC3339Nano, latest)
+	if err != nil {
+		return 0, fmt.Errorf("invalid latest block time: %w", err)
+	}
+	refT, err := time.Parse(time.RFC3339Nano, refLatest)
+	if err != nil {
+		return 0, fmt.Errorf("invalid reference latest block time: %w", err)
+	}
+	return refT.Sub(t), nil
+}
@@ -0,0 +1,102 @@
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/metrics"
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// referenceNode answers status with its latest block, and block with its final
+// block, or the block hash for any height, unknown when empty.
+type referenceNode struct {
+	height      uint64
+	hash        string
+	blockTime   string
+	finalHeight uint64
+	finalHash   string
+	blockHash   string
+}
+
+func (n referenceNode) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	var payload struct {
+		Method string                 `json:"method"`
+		Params map[string]interface{} `json:"params"`
+	}
+	_ = json.NewDecoder(req.Body).Decode(&payload)
+
+	result := map[string]interface{}{"header": map[string]interface{}{"hash": n.blockHash}}
+	switch {
+	case payload.Method == "status":
+		result = map[string]interface{}{"sync_info": map[string]interface{}{
+			"latest_block_height": n.height,
+			"latest_block_hash":   n.hash,
+			"latest_block_time":   n.blockTime,
+		}}
+	case payload.Params["finality"] == "final":
+		result = map[string]interface{}{"header": map[string]interface{}{"height": n.finalHeight, "hash": n.finalHash}}
+	case n.blockHash == "":
+		_ = json.NewEncoder(res).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": "dontcare", "error": map[string]interface{}{
+			"name":  "HANDLER_ERROR",
+			"code":  -32000,
+			"cause": map[string]interface{}{"name": "UNKNOWN_BLOCK"},
+		}})
+		return
+	}
+	_ = json.NewEncoder(res).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": "dontcare", "result": result})
+}
+
+func TestCollectReferences(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		node    = httptest.NewServer(referenceNode{finalHeight: 98, finalHash: "hash98", blockHash: "hash93"})
+		ahead   = httptest.NewServer(referenceNode{110, "hash110", "2023-10-18T13:35:42.5Z", 108, "hash108", "hash98"})
+		behind  = httptest.NewServer(referenceNode{95, "hash95", "2023-10-18T13:35:33Z", 93, "hash93", "hash93"})
+		forked  = httptest.NewServer(referenceNode{100, "other100", "2023-10-18T13:35:36Z", 98, "other98", ""})
+		pruned  = httptest.NewServer(referenceNode{105, "other105", "2023-10-18T13:35:39Z", 103, "other103", ""})
+		metrics = metrics.New("near_validator_watcher")
+		watcher = New(near.NewClient([]string{node.URL}), metrics, &Config{
+			References: map[string]*near.Client{
+				"ahead":  near.NewClient([]string{ahead.URL}),
+				"behind": near.NewClient([]string{behind.URL}),
+				"forked": near.NewClient([]string{forked.URL}),
+			},
+		})
+	)
+	defer node.Close()
+	defer ahead.Close()
+	defer behind.Close()
+	defer forked.Close()
+	defer pruned.Close()
+
+	// The optimistic blocks differ, only final blocks are compared
+	var status near.StatusResponse
+	status.SyncInfo.LatestBlockHeight = 100
+	status.SyncInfo.LatestBlockHash = "hash100"
+	status.SyncInfo.LatestBlockTime = "2023-10-18T13:35:36Z"
+
+	require.NoError(t, watcher.collectReferences(ctx, status))
+
+	assert.Equal(t, float64(10), testutil.ToFloat64(metrics.ReferenceNodeHeightLag.WithLabelValues("ahead")))
+	assert.Equal(t, 6.5, testutil.ToFloat64(metrics.ReferenceNodeTimeLag.WithLabelValues("ahead")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(metrics.ReferenceNodeFork.WithLabelValues("ahead")))
+
+	assert.Equal(t, float64(-5), testutil.ToFloat64(metrics.ReferenceNodeHeightLag.WithLabelValues("behind")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(metrics.ReferenceNodeFork.WithLabelValues("behind")))
+
+	assert.Equal(t, float64(0), testutil.ToFloat64(metrics.ReferenceNodeHeightLag.WithLabelValues("forked")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.ReferenceNodeFork.WithLabelValues("forked")))
+
+	// The fork state is unknown once the block to compare is garbage collected
+	watcher.config.References = map[string]*near.Client{"forked": near.NewClient([]string{pruned.URL})}
+	require.NoError(t, watcher.collectReferences(ctx, status))
+	assert.Equal(t, float64(5), testutil.ToFloat64(metrics.ReferenceNodeHeightLag.WithLabelValues("forked")))
+	assert.Equal(t, 2, testutil.CollectAndCount(metrics.ReferenceNodeFork), "only ahead and behind are left")
+}
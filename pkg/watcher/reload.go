@@ -0,0 +1,124 @@
+package watcher
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Tracking holds the settings of the tracked validators that can be reloaded
+// while the watcher is running.
+type Tracking struct {
+	TrackedAccounts      []string
+	Validators           map[string]ValidatorConfig
+	AlertUptimeThreshold float64
+}
+
+// Reload schedules new tracking settings, they are applied at once before the
+// next collection cycle.
+func (w *Watcher) Reload(tracking Tracking) {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	w.pendingReload = &tracking
+}
+
+// applyReload replaces the tracking settings by the pending ones if any. It
+// must only be called between collection cycles.
+func (w *Watcher) applyReload() {
+	w.reloadMu.Lock()
+	tracking := w.pendingReload
+	w.pendingReload = nil
+	w.reloadMu.Unlock()
+
+	if tracking == nil {
+		return
+	}
+
+	changes := diffTracking(w.config, tracking)
+	if len(changes) == 0 {
+		logrus.Info("configuration reloaded, tracked validators unchanged")
+	}
+	for _, change := range changes {
+		entry := logrus.NewEntry(logrus.StandardLogger())
+		if change.accountID != "" {
+			entry = entry.WithField("account_id", change.accountID)
+		}
+		entry.Infof("configuration reloaded: %s", change.description)
+	}
+
+	previous := w.config.TrackedAccounts
+	w.config.TrackedAccounts = tracking.TrackedAccounts
+	w.config.Validators = tracking.Validators
+	w.config.AlertUptimeThreshold = tracking.AlertUptimeThreshold
+
+	// Alerts of untracked validators would otherwise keep firing forever
+	for _, account := range previous {
+		if w.isTracked(account) == "1" {
+			continue
+		}
+		for _, name := range []string{alertValidatorKickedOut, alertValidatorNotInNext, alertValidatorUptimeLow} {
+			w.config.Alerts.Resolve(w.config.alertKey(name, account))
+		}
+	}
+}
+
+type trackingChange struct {
+	accountID   string
+	description string
+}
+
+func diffTracking(config *Config, tracking *Tracking) []trackingChange {
+	var (
+		changes  = make([]trackingChange, 0)
+		previous = make(map[string]bool)
+		next     = make(map[string]bool)
+	)
+	for _, account := range config.TrackedAccounts {
+		previous[account] = true
+	}
+	for _, account := range tracking.TrackedAccounts {
+		next[account] = true
+	}
+
+	for _, account := range tracking.TrackedAccounts {
+		if !previous[account] {
+			changes = append(changes, trackingChange{account, "validator added"})
+			continue
+		}
+
+		before, after := config.Validators[account], tracking.Validators[account]
+		if before.Name != after.Name {
+			changes = append(changes, trackingChange{account, fmt.Sprintf("name changed from %q to %q", before.Name, after.Name)})
+		}
+		if before.UptimeThreshold != after.UptimeThreshold {
+			changes = append(changes, trackingChange{account, fmt.Sprintf("uptime threshold changed from %s to %s",
+				prettyPrintFloat(before.UptimeThreshold),
+				prettyPrintFloat(after.UptimeThreshold),
+			)})
+		}
+		if fmt.Sprint(before.Labels) != fmt.Sprint(after.Labels) {
+			changes = append(changes, trackingChange{account, fmt.Sprintf("labels changed from %v to %v", before.Labels, after.Labels)})
+		}
+	}
+
+	for _, account := range config.TrackedAccounts {
+		if !next[account] {
+			changes = append(changes, trackingChange{account, "validator removed"})
+		}
+	}
+
+	if config.AlertUptimeThreshold != tracking.AlertUptimeThreshold {
+		changes = append(changes, trackingChange{"", fmt.Sprintf("alert uptime threshold changed from %s to %s",
+			prettyPrintFloat(config.AlertUptimeThreshold),
+			prettyPrintFloat(tracking.AlertUptimeThreshold),
+		)})
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].accountID < changes[j].accountID
+	})
+
+	return changes
+}
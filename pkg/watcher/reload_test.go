@@ -0,0 +1,44 @@
+package watcher
+
+import (
+	"testing"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/alert"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReload(t *testing.T) {
+	alerts := alert.NewManager(nil)
+	watcher := New(nil, nil, &Config{
+		TrackedAccounts: []string{"a.poolv1.near", "b.poolv1.near"},
+		Validators: map[string]ValidatorConfig{
+			"a.poolv1.near": {Name: "A", UptimeThreshold: 95},
+		},
+		Alerts:               alerts,
+		AlertUptimeThreshold: 90,
+	})
+	alerts.Fire(alert.Alert{Key: watcher.config.alertKey(alertValidatorUptimeLow, "b.poolv1.near")})
+
+	tracking := Tracking{
+		TrackedAccounts: []string{"a.poolv1.near", "c.poolv1.near"},
+		Validators: map[string]ValidatorConfig{
+			"a.poolv1.near": {Name: "A", UptimeThreshold: 98},
+		},
+		AlertUptimeThreshold: 90,
+	}
+	assert.Equal(t, []trackingChange{
+		{"a.poolv1.near", "uptime threshold changed from 95 to 98"},
+		{"b.poolv1.near", "validator removed"},
+		{"c.poolv1.near", "validator added"},
+	}, diffTracking(watcher.config, &tracking))
+
+	// Nothing changes until the reload is applied
+	watcher.Reload(tracking)
+	assert.Equal(t, "1", watcher.isTracked("b.poolv1.near"))
+
+	watcher.applyReload()
+	assert.Equal(t, "0", watcher.isTracked("b.poolv1.near"))
+	assert.Equal(t, "1", watcher.isTracked("c.poolv1.near"))
+	assert.Equal(t, float64(98), watcher.config.uptimeThreshold("a.poolv1.near"))
+	assert.Empty(t, alerts.Firing())
+}
@@ -0,0 +1,159 @@
+package watcher
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+const year = 365 * 24 * time.Hour
+
+// Rewards is the reward of a staking pool over its last full epoch. The
+// protocol distributes the rewards of an epoch at the first block of the next
+// one, so the reward is the increase of the pool account balance between the
+// last block of the epoch and the first block of the next one, which deposits
+// and withdrawals made during the epoch don't change.
+type Rewards struct {
+	EpochHeight int64
+	// Reward is in yoctoNEAR
+	Reward decimal.Decimal
+	// BlockTime is the average time between blocks during the epoch
+	BlockTime time.Duration
+	// APY and NetAPY are the compounded annual returns in percent, before and
+	// after the reward fee of the pool
+	APY    float64
+	NetAPY float64
+}
+
+// stakeSample is the stake of a pool as a validator of an epoch, and the
+// reward distributed to it at the first block of that epoch for the previous
+// one.
+type stakeSample struct {
+	epochHeight int64
+	height      int64
+	time        time.Time
+	stake       decimal.Decimal
+	reward      decimal.Decimal
+}
+
+// collectRewards samples the tracked staking pools once per epoch and computes
+// their rewards when two consecutive epochs are sampled.
+func (w *Watcher) collectRewards(ctx context.Context, validators near.ValidatorsResponse, config near.ProtocolConfigResponse) error {
+	logrus.Debug("collect rewards")
+
+	if w.stakeSamples == nil {
+		w.stakeSamples = make(map[string]stakeSample)
+		w.rewards = make(map[string]Rewards)
+	}
+
+	for _, account := range w.config.TrackedAccounts {
+		pool, ok := w.stakingPools[account]
+		if !ok {
+			continue
+		}
+		previous, ok := w.stakeSamples[account]
+		if ok && previous.epochHeight == validators.EpochHeight {
+			continue
+		}
+
+		sample, err := w.sampleStake(ctx, account, validators)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			logrus.WithError(err).WithField("account_id", account).Warn("failed to sample staking pool reward at epoch start")
+			// Only retry on the next cycle when the error is transient
+			if !near.IsRetryable(err) {
+				w.stakeSamples[account] = stakeSample{epochHeight: validators.EpochHeight}
+			}
+			continue
+		}
+		w.stakeSamples[account] = sample
+
+		if !ok || previous.epochHeight != sample.epochHeight-1 || previous.stake.IsZero() {
+			continue
+		}
+
+		rewards := computeRewards(previous, sample, config.EpochLength, pool.RewardFeeFraction)
+		w.rewards[account] = rewards
+
+		w.metrics.StakingPoolEpochReward.WithLabelValues(account).Set(rewards.Reward.Div(yoctoUnit).InexactFloat64())
+		w.metrics.StakingPoolAPY.WithLabelValues(account).Set(rewards.APY)
+		w.metrics.StakingPoolNetAPY.WithLabelValues(account).Set(rewards.NetAPY)
+
+		logrus.WithFields(logrus.Fields{
+			"account_id":   account,
+			"epoch_height": rewards.EpochHeight,
+			"reward":       rewards.Reward.Div(yoctoUnit).StringFixed(2),
+			"apy":          prettyPrintFloat(rewards.APY),
+			"net_apy":      prettyPrintFloat(rewards.NetAPY),
+		}).Info("computed staking pool rewards")
+	}
+
+	return nil
+}
+
+// sampleStake returns the stake of the pool in the current epoch and the reward
+// distributed at its first block: the increase of the total balance of the
+// pool, locked or not, since the previous block. Stake returned at the epoch
+// change moves from the locked to the liquid balance and doesn't change it.
+func (w *Watcher) sampleStake(ctx context.Context, account string, validators near.ValidatorsResponse) (stakeSample, error) {
+	block, err := w.client.Block(ctx, uint64(validators.EpochStartHeight))
+	if err != nil {
+		return stakeSample{}, err
+	}
+
+	before, err := w.client.ViewAccount(ctx, account, near.QueryWithBlockHeight(int64(block.Header.PrevHeight)))
+	if err != nil {
+		return stakeSample{}, err
+	}
+	after, err := w.client.ViewAccount(ctx, account, near.QueryWithBlockHeight(validators.EpochStartHeight))
+	if err != nil {
+		return stakeSample{}, err
+	}
+
+	sample := stakeSample{
+		epochHeight: validators.EpochHeight,
+		height:      validators.EpochStartHeight,
+		time:        time.Unix(0, block.Header.Timestamp),
+		reward:      after.Amount.Add(after.Locked).Sub(before.Amount).Sub(before.Locked),
+	}
+	for _, v := range validators.CurrentValidators {
+		if v.AccountId == account {
+			sample.stake = v.Stake
+		}
+	}
+	return sample, nil
+}
+
+// computeRewards annualises the reward of the epoch of the first sample,
+// distributed at the start of the next one, relative to the stake of the pool
+// during the epoch. The epoch duration is the epoch length times the block time
+// observed between the samples.
+func computeRewards(start stakeSample, end stakeSample, epochLength int, fee near.RewardFeeFraction) Rewards {
+	rewards := Rewards{
+		EpochHeight: start.epochHeight,
+		Reward:      end.reward,
+	}
+	if end.height > start.height {
+		rewards.BlockTime = end.time.Sub(start.time) / time.Duration(end.height-start.height)
+	}
+
+	epochDuration := rewards.BlockTime * time.Duration(epochLength)
+	if epochDuration <= 0 {
+		return rewards
+	}
+	epochsPerYear := float64(year) / float64(epochDuration)
+
+	rate := rewards.Reward.Div(start.stake).InexactFloat64()
+	netRate := rate * (1 - fee.Percent()/100)
+
+	rewards.APY = 100 * (math.Pow(1+rate, epochsPerYear) - 1)
+	rewards.NetAPY = 100 * (math.Pow(1+netRate, epochsPerYear) - 1)
+
+	return rewards
+}
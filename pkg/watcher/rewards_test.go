@@ -0,0 +1,129 @@
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/metrics"
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// poolNode answers block with the previous height and time of the first block
+// of each epoch, and view_account with the balances of the pool at each height,
+// in NEAR.
+type poolNode struct {
+	blocks   map[int64][2]int64
+	balances map[int64][2]int64
+}
+
+func (n poolNode) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	var payload struct {
+		Method string `json:"method"`
+		Params struct {
+			BlockID int64 `json:"block_id"`
+		} `json:"params"`
+	}
+	_ = json.NewDecoder(req.Body).Decode(&payload)
+
+	var result interface{}
+	if payload.Method == "block" {
+		block := n.blocks[payload.Params.BlockID]
+		result = map[string]interface{}{"header": map[string]interface{}{
+			"height":      payload.Params.BlockID,
+			"prev_height": block[0],
+			"timestamp":   block[1],
+		}}
+	} else {
+		balance := n.balances[payload.Params.BlockID]
+		result = map[string]interface{}{
+			"amount": yoctoUnit.Mul(decimal.NewFromInt(balance[0])).String(),
+			"locked": yoctoUnit.Mul(decimal.NewFromInt(balance[1])).String(),
+		}
+	}
+	_ = json.NewEncoder(res).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": "dontcare", "result": result})
+}
+
+func TestComputeRewards(t *testing.T) {
+	var (
+		start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		near1 = yoctoUnit
+	)
+
+	rewards := computeRewards(
+		stakeSample{epochHeight: 10, height: 1000, time: start, stake: near1.Mul(decimal.NewFromInt(1000))},
+		stakeSample{epochHeight: 11, height: 44200, time: start.Add(43200 * time.Second), reward: near1},
+		43200,
+		near.RewardFeeFraction{Numerator: 10, Denominator: 100},
+	)
+
+	assert.Equal(t, int64(10), rewards.EpochHeight)
+	assert.True(t, near1.Equal(rewards.Reward))
+	assert.Equal(t, time.Second, rewards.BlockTime)
+	assert.InDelta(t, 100*(math.Pow(1.001, 730)-1), rewards.APY, 1e-6)
+	assert.InDelta(t, 100*(math.Pow(1.0009, 730)-1), rewards.NetAPY, 1e-6)
+}
+
+func TestCollectRewards(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		start   = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		account = "kiln.poolv1.near"
+		server  = httptest.NewServer(poolNode{
+			blocks: map[int64][2]int64{
+				1000:  {999, start.UnixNano()},
+				44200: {44199, start.Add(43200 * time.Second).UnixNano()},
+			},
+			balances: map[int64][2]int64{
+				999:  {10, 1000},
+				1000: {10, 1002},
+				// 500 NEAR deposited during the epoch, and 20 NEAR of unstaked
+				// balance returned at its end
+				44199: {10, 1520},
+				44200: {30, 1501},
+			},
+		})
+		metrics = metrics.New("near_validator_watcher")
+		watcher = New(near.NewClient([]string{server.URL}), metrics, &Config{
+			TrackedAccounts: []string{account},
+		})
+		config = near.ProtocolConfigResponse{EpochLength: 43200}
+	)
+	defer server.Close()
+
+	watcher.stakingPools = map[string]near.StakingPool{
+		account: {RewardFeeFraction: near.RewardFeeFraction{Numerator: 10, Denominator: 100}},
+	}
+	validators := func(epochHeight int64, epochStartHeight int64, stake int64) near.ValidatorsResponse {
+		resp := near.ValidatorsResponse{EpochHeight: epochHeight, EpochStartHeight: epochStartHeight}
+		resp.CurrentValidators = []near.CurrentEpochValidatorInfo{
+			{Validator: near.Validator{AccountId: account, Stake: yoctoUnit.Mul(decimal.NewFromInt(stake))}},
+		}
+		return resp
+	}
+
+	// A single epoch sampled, the block time is unknown
+	require.NoError(t, watcher.collectRewards(ctx, validators(10, 1000, 1000), config))
+	assert.True(t, yoctoUnit.Mul(decimal.NewFromInt(2)).Equal(watcher.stakeSamples[account].reward))
+	assert.Empty(t, watcher.rewards)
+
+	// The deposit isn't counted as reward
+	require.NoError(t, watcher.collectRewards(ctx, validators(11, 44200, 1500), config))
+	rewards, ok := watcher.rewards[account]
+	require.True(t, ok)
+	assert.Equal(t, int64(10), rewards.EpochHeight)
+	assert.True(t, yoctoUnit.Equal(rewards.Reward), rewards.Reward.String())
+	assert.Equal(t, time.Second, rewards.BlockTime)
+	assert.InDelta(t, 100*(math.Pow(1.001, 730)-1), rewards.APY, 1e-6)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.StakingPoolEpochReward.WithLabelValues(account)))
+	assert.InDelta(t, 100*(math.Pow(1.0009, 730)-1), testutil.ToFloat64(metrics.StakingPoolNetAPY.WithLabelValues(account)), 1e-6)
+}
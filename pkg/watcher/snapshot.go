@@ -0,0 +1,94 @@
+package watcher
+
+import (
+	"time"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/history"
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/shopspring/decimal"
+)
+
+// Snapshot holds the data of the last successful collection cycle, it must
+// not be modified.
+type Snapshot struct {
+	Network         string
+	Status          near.StatusResponse
+	Validators      near.ValidatorsResponse
+	ProtocolConfig  near.ProtocolConfigResponse
+	TrackedAccounts []string
+	// SeatPrice of the current epoch, nil when it couldn't be computed
+	SeatPrice *decimal.Decimal
+	// Rewards holds the last rewards computed for the tracked staking pools
+	Rewards     map[string]Rewards
+	CollectedAt time.Time
+
+	names map[string]string
+}
+
+// IsTracked returns whether the account was tracked when the data was collected.
+func (s *Snapshot) IsTracked(accountID string) bool {
+	for _, account := range s.TrackedAccounts {
+		if account == accountID {
+			return true
+		}
+	}
+	return false
+}
+
+// DisplayName returns the configured name of the account, or its shortened id.
+func (s *Snapshot) DisplayName(accountID string) string {
+	if name := s.names[accountID]; name != "" {
+		return name
+	}
+	return prettyPrintAccountID(accountID)
+}
+
+// Snapshot returns the data of the last successful collection cycle, or nil
+// until data is collected.
+func (w *Watcher) Snapshot() *Snapshot {
+	return w.snapshot.Load()
+}
+
+// History returns the epoch history store, nil when disabled.
+func (w *Watcher) History() *history.Store {
+	return w.config.History
+}
+
+func (w *Watcher) storeSnapshot(status near.StatusResponse, validators near.ValidatorsResponse, config near.ProtocolConfigResponse) {
+	rewards := make(map[string]Rewards, len(w.rewards))
+	for account, r := range w.rewards {
+		rewards[account] = r
+	}
+	names := make(map[string]string, len(w.config.Validators))
+	for account, v := range w.config.Validators {
+		names[account] = v.Name
+	}
+
+	w.snapshot.Store(&Snapshot{
+		Network:         w.config.Network,
+		Status:          status,
+		Validators:      validators,
+		ProtocolConfig:  config,
+		TrackedAccounts: w.config.TrackedAccounts,
+		SeatPrice:       w.seatPrice,
+		Rewards:         rewards,
+		CollectedAt:     time.Now().UTC(),
+		names:           names,
+	})
+}
+
+// EpochProgress returns the number of heights elapsed and remaining in the epoch.
+func (s *Snapshot) EpochProgress() (int64, int64) {
+	return epochProgress(s.Validators, s.ProtocolConfig, s.Status.SyncInfo.LatestBlockHeight)
+}
+
+// KickoutThresholds returns the blocks and chunks kickout thresholds in percent.
+func (s *Snapshot) KickoutThresholds() (int, int) {
+	return kickoutThresholds(s.ProtocolConfig)
+}
+
+// EndorsementsKickoutThreshold returns the endorsements kickout threshold of
+// chunk validators only in percent.
+func (s *Snapshot) EndorsementsKickoutThreshold() int {
+	return endorsementsKickoutThreshold(s.ProtocolConfig)
+}
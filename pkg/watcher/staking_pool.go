@@ -0,0 +1,132 @@
+package watcher
+
+import (
+	"context"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/metrics"
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// collectStakingPools calls the view methods of the staking pool contract of
+// every tracked account. Tracked accounts which aren't staking pools are only
+// logged.
+func (w *Watcher) collectStakingPools(ctx context.Context, validators near.ValidatorsResponse) error {
+	logrus.Debug("collect staking pools")
+
+	if w.stakingPools == nil {
+		w.stakingPools = make(map[string]near.StakingPool)
+	}
+	if w.stakingPoolEpochs == nil {
+		w.stakingPoolEpochs = make(map[string]int64)
+	}
+
+	for _, account := range w.config.TrackedAccounts {
+		pool, err := w.stakingPool(ctx, account, validators.EpochHeight)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			logrus.WithError(err).WithField("account_id", account).Warn("failed to get staking pool")
+			continue
+		}
+
+		if previous, ok := w.stakingPools[account]; ok {
+			logStakingPoolChanges(account, previous, pool)
+		}
+		w.stakingPools[account] = pool
+
+		w.metrics.StakingPoolAccounts.WithLabelValues(account).Set(float64(pool.NumberOfAccounts))
+		w.metrics.StakingPoolInfo.DeletePartialMatch(prometheus.Labels{"account_id": account})
+		w.metrics.StakingPoolInfo.WithLabelValues(account, pool.OwnerID, pool.StakingKey).Set(1)
+		w.metrics.StakingPoolPaused.WithLabelValues(account).Set(metrics.BoolToFloat64(pool.StakingPaused))
+		w.metrics.StakingPoolRewardFee.WithLabelValues(account).Set(pool.RewardFeeFraction.Percent())
+		w.metrics.StakingPoolTotalStakedBalance.WithLabelValues(account).Set(pool.TotalStakedBalance.Div(yoctoUnit).InexactFloat64())
+
+		if publicKey, ok := validatorPublicKey(validators, account); ok {
+			mismatch := publicKey != pool.StakingKey
+			if mismatch {
+				logrus.WithFields(logrus.Fields{
+					"account_id":  account,
+					"staking_key": pool.StakingKey,
+					"public_key":  publicKey,
+				}).Error("staking key of the pool differs from the validator public key")
+			}
+			w.metrics.StakingPoolStakingKeyMismatch.WithLabelValues(account).Set(metrics.BoolToFloat64(mismatch))
+		}
+	}
+
+	return nil
+}
+
+// stakingPool returns the staking pool deployed on the account. Its settings
+// rarely change and are only called once per epoch, a new staking key being
+// only used by the proposals of the next epochs anyway.
+func (w *Watcher) stakingPool(ctx context.Context, account string, epochHeight int64) (near.StakingPool, error) {
+	pool, ok := w.stakingPools[account]
+	if !ok || w.stakingPoolEpochs[account] != epochHeight {
+		settings, err := w.client.StakingPoolSettings(ctx, account)
+		if err != nil {
+			return pool, err
+		}
+		pool.OwnerID = settings.OwnerID
+		pool.RewardFeeFraction = settings.RewardFeeFraction
+		pool.StakingKey = settings.StakingKey
+	}
+
+	state, err := w.client.StakingPoolState(ctx, account)
+	if err != nil {
+		return pool, err
+	}
+	pool.TotalStakedBalance = state.TotalStakedBalance
+	pool.NumberOfAccounts = state.NumberOfAccounts
+	pool.StakingPaused = state.StakingPaused
+
+	w.stakingPoolEpochs[account] = epochHeight
+	return pool, nil
+}
+
+func logStakingPoolChanges(account string, previous near.StakingPool, pool near.StakingPool) {
+	entry := logrus.WithField("account_id", account)
+
+	if previous.RewardFeeFraction != pool.RewardFeeFraction {
+		entry.Warnf("staking pool reward fee changed from %s%% to %s%%",
+			prettyPrintFloat(previous.RewardFeeFraction.Percent()),
+			prettyPrintFloat(pool.RewardFeeFraction.Percent()),
+		)
+	}
+	if previous.OwnerID != pool.OwnerID {
+		entry.Warnf("staking pool owner changed from %s to %s", previous.OwnerID, pool.OwnerID)
+	}
+	if previous.StakingKey != pool.StakingKey {
+		entry.Warnf("staking pool staking key changed from %s to %s", previous.StakingKey, pool.StakingKey)
+	}
+	if !previous.StakingPaused && pool.StakingPaused {
+		entry.Warn("staking pool paused staking")
+	}
+	if previous.StakingPaused && !pool.StakingPaused {
+		entry.Info("staking pool resumed staking")
+	}
+}
+
+// validatorPublicKey returns the public key registered for the account, looked
+// up in the current validators, then the next ones, then the proposals.
+func validatorPublicKey(validators near.ValidatorsResponse, account string) (string, bool) {
+	for _, v := range validators.CurrentValidators {
+		if v.AccountId == account {
+			return v.PublicKey, true
+		}
+	}
+	for _, v := range validators.NextValidators {
+		if v.AccountId == account {
+			return v.PublicKey, true
+		}
+	}
+	for _, v := range validators.CurrentProposals {
+		if v.AccountId == account {
+			return v.PublicKey, true
+		}
+	}
+	return "", false
+}
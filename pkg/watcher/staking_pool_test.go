@@ -0,0 +1,152 @@
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/metrics"
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stakingPoolContract answers the view methods of the staking pools with their
+// results by account then method, and counts the calls of each method.
+type stakingPoolContract struct {
+	mu      sync.Mutex
+	results map[string]map[string]string
+	calls   map[string]int
+}
+
+func newStakingPoolContract(results map[string]map[string]string) *stakingPoolContract {
+	return &stakingPoolContract{results: results, calls: make(map[string]int)}
+}
+
+func (c *stakingPoolContract) set(account string, method string, result string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results[account][method] = result
+}
+
+func (c *stakingPoolContract) count(method string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls[method]
+}
+
+func (c *stakingPoolContract) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var payload struct {
+		Params near.QueryRequest `json:"params"`
+	}
+	_ = json.NewDecoder(req.Body).Decode(&payload)
+	c.calls[payload.Params.MethodName]++
+
+	result, ok := c.results[payload.Params.AccountID][payload.Params.MethodName]
+	if !ok {
+		_, _ = fmt.Fprint(res, `{"jsonrpc": "2.0", "id": "dontcare", "result": {"error": "MethodNotFound", "logs": []}}`)
+		return
+	}
+	// Results are returned as arrays of bytes
+	bytes := make([]int, 0, len(result))
+	for _, b := range []byte(result) {
+		bytes = append(bytes, int(b))
+	}
+	raw, _ := json.Marshal(bytes)
+	_, _ = fmt.Fprintf(res, `{"jsonrpc": "2.0", "id": "dontcare", "result": {"result": %s, "logs": []}}`, raw)
+}
+
+func stakingPoolResults() map[string]string {
+	return map[string]string{
+		"get_owner_id":             `"kiln.near"`,
+		"get_reward_fee_fraction":  `{"numerator": 7, "denominator": 100}`,
+		"get_total_staked_balance": `"23052051536090128427622637823"`,
+		"get_number_of_accounts":   `42`,
+		"is_staking_paused":        `false`,
+		"get_staking_key":          `"ed25519:9NBTDmtpY4ALxD2WRqbNC2FX5ATcGdbk8tLWKBbVJoRX"`,
+	}
+}
+
+func TestStakingPoolSettingsPerEpoch(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		contract = newStakingPoolContract(map[string]map[string]string{"kiln.poolv1.near": stakingPoolResults()})
+		server   = httptest.NewServer(contract)
+		metrics  = metrics.New("near_validator_watcher")
+		watcher  = New(near.NewClient([]string{server.URL}), metrics, &Config{
+			TrackedAccounts: []string{"kiln.poolv1.near"},
+		})
+	)
+	defer server.Close()
+
+	require.NoError(t, watcher.collectStakingPools(ctx, near.ValidatorsResponse{EpochHeight: 10}))
+	assert.Equal(t, 1, contract.count("get_reward_fee_fraction"))
+	assert.Equal(t, 1, contract.count("get_total_staked_balance"))
+
+	// The settings are only called again at the next epoch
+	contract.set("kiln.poolv1.near", "get_reward_fee_fraction", `{"numerator": 5, "denominator": 100}`)
+	contract.set("kiln.poolv1.near", "get_number_of_accounts", `43`)
+	require.NoError(t, watcher.collectStakingPools(ctx, near.ValidatorsResponse{EpochHeight: 10}))
+	assert.Equal(t, 1, contract.count("get_owner_id"))
+	assert.Equal(t, 1, contract.count("get_reward_fee_fraction"))
+	assert.Equal(t, 1, contract.count("get_staking_key"))
+	assert.Equal(t, 2, contract.count("get_total_staked_balance"))
+	assert.Equal(t, float64(7), testutil.ToFloat64(metrics.StakingPoolRewardFee.WithLabelValues("kiln.poolv1.near")))
+	assert.Equal(t, float64(43), testutil.ToFloat64(metrics.StakingPoolAccounts.WithLabelValues("kiln.poolv1.near")))
+
+	require.NoError(t, watcher.collectStakingPools(ctx, near.ValidatorsResponse{EpochHeight: 11}))
+	assert.Equal(t, 2, contract.count("get_reward_fee_fraction"))
+	assert.Equal(t, float64(5), testutil.ToFloat64(metrics.StakingPoolRewardFee.WithLabelValues("kiln.poolv1.near")))
+}
+
+func TestCollectStakingPools(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		contract = newStakingPoolContract(map[string]map[string]string{
+			"kiln.poolv1.near":  stakingPoolResults(),
+			"other.poolv1.near": stakingPoolResults(),
+		})
+		server  = httptest.NewServer(contract)
+		metrics = metrics.New("near_validator_watcher")
+		watcher = New(near.NewClient([]string{server.URL}), metrics, &Config{
+			TrackedAccounts: []string{"kiln.poolv1.near", "other.poolv1.near"},
+		})
+	)
+	defer server.Close()
+
+	var validators near.ValidatorsResponse
+	validators.CurrentValidators = []near.CurrentEpochValidatorInfo{
+		{Validator: near.Validator{AccountId: "kiln.poolv1.near", PublicKey: "ed25519:9NBTDmtpY4ALxD2WRqbNC2FX5ATcGdbk8tLWKBbVJoRX"}},
+		{Validator: near.Validator{AccountId: "other.poolv1.near", PublicKey: "ed25519:other"}},
+	}
+
+	// A failing view method only skips its staking pool
+	delete(contract.results["other.poolv1.near"], "is_staking_paused")
+	require.NoError(t, watcher.collectStakingPools(ctx, validators))
+
+	assert.Equal(t, float64(42), testutil.ToFloat64(metrics.StakingPoolAccounts.WithLabelValues("kiln.poolv1.near")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.StakingPoolInfo.WithLabelValues("kiln.poolv1.near", "kiln.near", "ed25519:9NBTDmtpY4ALxD2WRqbNC2FX5ATcGdbk8tLWKBbVJoRX")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(metrics.StakingPoolPaused.WithLabelValues("kiln.poolv1.near")))
+	assert.Equal(t, float64(7), testutil.ToFloat64(metrics.StakingPoolRewardFee.WithLabelValues("kiln.poolv1.near")))
+	assert.InDelta(t, 23052.05, testutil.ToFloat64(metrics.StakingPoolTotalStakedBalance.WithLabelValues("kiln.poolv1.near")), 0.01)
+	assert.Equal(t, float64(0), testutil.ToFloat64(metrics.StakingPoolStakingKeyMismatch.WithLabelValues("kiln.poolv1.near")))
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.StakingPoolAccounts))
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.StakingPoolStakingKeyMismatch))
+
+	// Once the view method answers, the staking key differs from the
+	// validator public key
+	contract.set("other.poolv1.near", "is_staking_paused", `true`)
+	require.NoError(t, watcher.collectStakingPools(ctx, validators))
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.StakingPoolPaused.WithLabelValues("other.poolv1.near")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.StakingPoolStakingKeyMismatch.WithLabelValues("other.poolv1.near")))
+	assert.Equal(t, 2, testutil.CollectAndCount(metrics.StakingPoolInfo))
+}
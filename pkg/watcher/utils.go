@@ -3,8 +3,15 @@ package watcher
 import (
 	"fmt"
 	"strings"
+
+	"github.com/kilnfi/near-validator-watcher/pkg/near"
 )
 
+// uptime returns the blocks and chunks uptime of the validator in percent.
+func uptime(v near.CurrentEpochValidatorInfo) (float64, float64) {
+	return v.BlocksUptime(), v.ChunksUptime()
+}
+
 func prettyPrintFloat(f float64) string {
 	if f == float64(int(f)) {
 		return fmt.Sprintf("%.0f", f)
@@ -2,18 +2,19 @@ package watcher
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
-	"github.com/avast/retry-go/v4"
 	"github.com/fatih/color"
 	"github.com/kilnfi/near-validator-watcher/pkg/metrics"
 	"github.com/kilnfi/near-validator-watcher/pkg/near"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
 )
@@ -25,7 +26,22 @@ type Watcher struct {
 	client  *near.Client
 	metrics *metrics.Metrics
 
-	isSynced atomic.Bool
+	health       healthState
+	blocks       blockFollower
+	stakingPools map[string]near.StakingPool
+	// Epoch height the settings of each staking pool were last called at
+	stakingPoolEpochs map[string]int64
+	stakeSamples      map[string]stakeSample
+	rewards           map[string]Rewards
+	inNextEpoch       map[string]bool
+
+	protocolConfig    *near.ProtocolConfigResponse
+	seatPrice         *decimal.Decimal
+	lastEpochSnapshot *epochSnapshot
+	snapshot          atomic.Pointer[Snapshot]
+
+	reloadMu      sync.Mutex
+	pendingReload *Tracking
 }
 
 func New(client *near.Client, metrics *metrics.Metrics, config *Config) *Watcher {
@@ -36,26 +52,21 @@ func New(client *near.Client, metrics *metrics.Metrics, config *Config) *Watcher
 	}
 }
 
-func (w *Watcher) IsSynced() bool {
-	return w.isSynced.Load()
-}
-
 func (w *Watcher) Start(ctx context.Context) error {
 	ticker := time.NewTicker(w.config.RefreshRate)
 
+	w.health.mu.Lock()
+	w.health.startedAt = time.Now()
+	w.health.mu.Unlock()
+
 	for {
-		retryOpts := []retry.Option{
-			retry.Context(ctx),
-			retry.Delay(1 * time.Second),
-			retry.Attempts(3),
-			retry.OnRetry(func(n uint, err error) {
-				logrus.WithError(err).Error("failed to collect data, retrying...")
-			}),
-		}
+		w.applyReload()
 
-		retry.Do(func() error {
-			return w.collectData(ctx)
-		}, retryOpts...)
+		// Failures are logged and counted by each collector
+		_ = w.collectData(ctx)
+		w.recordCycle()
+
+		w.collectEndpoints()
 
 		select {
 		case <-ctx.Done():
@@ -66,21 +77,189 @@ func (w *Watcher) Start(ctx context.Context) error {
 	}
 }
 
+// collectData runs the collectors of a cycle. Status, validators and protocol
+// config are fetched concurrently, then the collectors depending on them run
+// concurrently as well. A failing collector only skips the ones depending on
+// it, the protocol config falling back to the last one fetched.
 func (w *Watcher) collectData(ctx context.Context) error {
-	status, err := w.collectStatus(ctx)
-	if err != nil {
-		return err
+	var (
+		status        near.StatusResponse
+		validators    near.ValidatorsResponse
+		config        near.ProtocolConfigResponse
+		wg            sync.WaitGroup
+		statusErr     error
+		validatorsErr error
+		configErr     error
+	)
+
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		statusErr = w.collect(ctx, collectorStatus, func(ctx context.Context) (err error) {
+			status, err = w.collectStatus(ctx)
+			return err
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		validatorsErr = w.collect(ctx, collectorValidators, func(ctx context.Context) (err error) {
+			validators, err = w.collectValidators(ctx)
+			return err
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		configErr = w.collect(ctx, collectorProtocolConfig, func(ctx context.Context) (err error) {
+			config, err = w.collectProtocolConfig(ctx)
+			return err
+		})
+	}()
+	wg.Wait()
+
+	// Any endpoint reachable answers the status, which is the simplest request
+	if ctx.Err() == nil {
+		w.evaluateRPCAlert(statusErr)
+		w.recordCollection(status, errors.Join(statusErr, validatorsErr))
 	}
-	validators, err := w.collectValidators(ctx)
-	if err != nil {
-		return err
+
+	if configErr == nil {
+		w.protocolConfig = &config
+	} else if w.protocolConfig != nil {
+		config = *w.protocolConfig
+	}
+	if statusErr != nil || validatorsErr != nil {
+		return errors.Join(statusErr, validatorsErr, configErr)
+	}
+
+	errs := make([]error, 4)
+	wg.Add(4)
+	go func() {
+		defer wg.Done()
+		errs[0] = w.collect(ctx, collectorEpochEnd, func(ctx context.Context) error {
+			return w.recordEpochEnd(ctx, status, validators)
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		errs[1] = w.collect(ctx, collectorBlocks, func(ctx context.Context) error {
+			return w.collectBlocks(ctx, validators)
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		errs[2] = w.collectStakingData(ctx, status, validators, config)
+	}()
+	go func() {
+		defer wg.Done()
+		if len(w.config.References) > 0 {
+			errs[3] = w.collect(ctx, collectorReferences, func(ctx context.Context) error {
+				return w.collectReferences(ctx, status)
+			})
+		}
+	}()
+	wg.Wait()
+
+	if w.protocolConfig != nil {
+		w.collectKickoutForecast(status, validators, config)
+		w.collectSeatPrice(validators, config)
+	}
+	w.collectNextEpoch(validators)
+
+	w.evaluateAlerts(status, validators)
+	w.storeSnapshot(status, validators, config)
+
+	w.printStatusLine(status, validators, config)
+
+	return errors.Join(append(errs, configErr)...)
+}
+
+// collectStakingData runs the collectors relying on the staking pools state.
+func (w *Watcher) collectStakingData(ctx context.Context, status near.StatusResponse, validators near.ValidatorsResponse, config near.ProtocolConfigResponse) error {
+	err := w.collect(ctx, collectorStakingPools, func(ctx context.Context) error {
+		return w.collectStakingPools(ctx, validators)
+	})
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	var rewardsErr error
+	if w.protocolConfig != nil {
+		rewardsErr = w.collect(ctx, collectorRewards, func(ctx context.Context) error {
+			return w.collectRewards(ctx, validators, config)
+		})
+	}
+	nodeKeyErr := w.collect(ctx, collectorNodeKey, func(ctx context.Context) error {
+		return w.collectNodeKey(ctx, status, validators)
+	})
+
+	return errors.Join(err, rewardsErr, nodeKeyErr)
+}
+
+func (w *Watcher) collectEndpoints() {
+	for _, e := range w.client.Endpoints() {
+		w.metrics.RPCEndpointActive.WithLabelValues(e.Name).Set(metrics.BoolToFloat64(e.Active))
+		w.metrics.RPCEndpointHealthy.WithLabelValues(e.Name).Set(metrics.BoolToFloat64(e.Healthy))
 	}
-	_, err = w.collectProtocolConfig(ctx)
+}
+
+func (w *Watcher) collectBlocks(ctx context.Context, validators near.ValidatorsResponse) error {
+	logrus.Debug("collect blocks")
+
+	skipped := len(w.blocks.pendingHeights)
+	blocks, err := w.blocks.walk(ctx, w.client)
+	for _, block := range blocks {
+		w.metrics.BlocksAuthored.WithLabelValues(block.Author, w.isTracked(block.Author)).Inc()
+
+		for _, chunk := range blockChunks(block) {
+			labelShardID := strconv.Itoa(chunk.ShardID)
+			if chunk.Included {
+				w.metrics.ShardChunksIncluded.WithLabelValues(labelShardID).Inc()
+			} else {
+				w.metrics.ShardChunksMissed.WithLabelValues(labelShardID).Inc()
+			}
+		}
+	}
+	w.metrics.SkippedBlocks.Add(float64(len(w.blocks.pendingHeights) - skipped))
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to walk blocks: %w", err)
+	}
+
+	missedBlocks, missedChunks, unattributed := w.blocks.attribute(validators)
+	if len(unattributed) > 0 {
+		logrus.WithField("heights", unattributed).Warn("skipped blocks missed by several validators")
+	}
+
+	for _, missed := range missedBlocks {
+		w.metrics.MissedBlocks.WithLabelValues(missed.AccountID, w.isTracked(missed.AccountID)).Add(float64(missed.Count))
+
+		entry := logrus.WithFields(logrus.Fields{
+			"account_id": missed.AccountID,
+			"tracked":    w.isTracked(missed.AccountID) == "1",
+			"count":      missed.Count,
+		})
+		switch len(missed.Heights) {
+		case 0:
+			entry.Warn("missed blocks")
+		case 1:
+			entry.WithField("height", missed.Heights[0]).Warn("missed block")
+		default:
+			entry.WithField("heights", missed.Heights).Warn("missed blocks")
+		}
 	}
 
-	w.printStatusLine(status, validators)
+	for _, missed := range missedChunks {
+		if w.isTracked(missed.AccountID) != "1" {
+			continue
+		}
+
+		w.metrics.MissedChunks.WithLabelValues(missed.AccountID, strconv.Itoa(missed.ShardID)).Add(float64(missed.Count))
+
+		logrus.WithFields(logrus.Fields{
+			"account_id": missed.AccountID,
+			"shard_id":   missed.ShardID,
+			"count":      missed.Count,
+		}).Warn("missed chunks")
+	}
 
 	return nil
 }
@@ -93,6 +272,9 @@ func (w *Watcher) collectProtocolConfig(ctx context.Context) (near.ProtocolConfi
 		return config, err
 	}
 
+	w.metrics.BlockProducerKickoutThreshold.Set(float64(config.BlockProducerKickoutThreshold))
+	w.metrics.ChunkProducerKickoutThreshold.Set(float64(config.ChunkProducerKickoutThreshold))
+	w.metrics.ChunkValidatorOnlyKickoutThreshold.Set(float64(config.ChunkValidatorOnlyKickoutThreshold))
 	w.metrics.EpochLength.Set(float64(config.EpochLength))
 	w.metrics.ProtocolVersion.Set(float64(config.ProtocolVersion))
 
@@ -107,8 +289,6 @@ func (w *Watcher) collectStatus(ctx context.Context) (near.StatusResponse, error
 		return status, fmt.Errorf("failed to get status: %w", err)
 	}
 
-	w.isSynced.Store(!status.SyncInfo.Syncing)
-
 	w.metrics.BlockNumber.Set(float64(status.SyncInfo.LatestBlockHeight))
 	w.metrics.ChainID.WithLabelValues(status.ChainID).Set(metrics.StringToFloat64(status.ChainID))
 	w.metrics.SyncingDesc.Set(metrics.BoolToFloat64(status.SyncInfo.Syncing))
@@ -132,19 +312,27 @@ func (w *Watcher) collectValidators(ctx context.Context) (near.ValidatorsRespons
 	w.metrics.ValidatorProducedBlocks.Reset()
 	w.metrics.ValidatorProducedChunks.Reset()
 	w.metrics.ValidatorProducedEndorsements.Reset()
+	w.metrics.ValidatorChunkValidatorOnly.Reset()
+	w.metrics.ValidatorShardExpectedChunks.Reset()
+	w.metrics.ValidatorShardExpectedEndorsements.Reset()
+	w.metrics.ValidatorShardProducedChunks.Reset()
+	w.metrics.ValidatorShardProducedEndorsements.Reset()
 	w.metrics.ValidatorSlashed.Reset()
 	w.metrics.ValidatorStake.Reset()
 	w.metrics.ValidatorRank.Reset()
 	w.metrics.NextValidatorStake.Reset()
 	w.metrics.CurrentProposals.Reset()
 	w.metrics.PrevEpochKickout.Reset()
+	w.metrics.PrevEpochKickoutExpected.Reset()
+	w.metrics.PrevEpochKickoutProduced.Reset()
+	w.metrics.PrevEpochKickoutProtocolVersion.Reset()
+	w.metrics.PrevEpochKickoutStake.Reset()
+	w.metrics.PrevEpochKickoutStakeThreshold.Reset()
 
 	labelEpochStartHeight := strconv.FormatInt(validators.EpochStartHeight, 10)
 
 	w.metrics.EpochStartHeight.Set(float64(validators.EpochStartHeight))
 
-	var seatPrice float64
-
 	// Sort validators by stake to be able to calculate their rank
 	rankedValidator := validators.CurrentValidators
 	sort.SliceStable(rankedValidator, func(i, j int) bool {
@@ -164,21 +352,20 @@ func (w *Watcher) collectValidators(ctx context.Context) (near.ValidatorsRespons
 		w.metrics.ValidatorProducedBlocks.WithLabelValues(labels...).Set(float64(v.NumProducedBlocks))
 		w.metrics.ValidatorProducedChunks.WithLabelValues(labels...).Set(float64(v.NumProducedChunks))
 		w.metrics.ValidatorProducedEndorsements.WithLabelValues(labels...).Set(float64(v.NumProducedEndorsements))
+		w.metrics.ValidatorChunkValidatorOnly.WithLabelValues(labels...).Set(metrics.BoolToFloat64(v.IsChunkValidatorOnly()))
+
+		for _, shard := range v.ShardStats() {
+			shardLabels := []string{v.AccountId, v.PublicKey, labelEpochStartHeight, w.isTracked(v.AccountId), strconv.Itoa(shard.ShardID)}
+			w.metrics.ValidatorShardExpectedChunks.WithLabelValues(shardLabels...).Set(float64(shard.ExpectedChunks))
+			w.metrics.ValidatorShardExpectedEndorsements.WithLabelValues(shardLabels...).Set(float64(shard.ExpectedEndorsements))
+			w.metrics.ValidatorShardProducedChunks.WithLabelValues(shardLabels...).Set(float64(shard.ProducedChunks))
+			w.metrics.ValidatorShardProducedEndorsements.WithLabelValues(shardLabels...).Set(float64(shard.ProducedEndorsements))
+		}
 
 		w.metrics.ValidatorSlashed.WithLabelValues(labels...).Set(metrics.BoolToFloat64(v.IsSlashed))
 		w.metrics.ValidatorStake.WithLabelValues(labels...).Set(v.Stake.Div(yoctoUnit).InexactFloat64())
-
-		t := v.Stake.Div(yoctoUnit).InexactFloat64()
-		if seatPrice == 0 {
-			seatPrice = t
-		}
-		if seatPrice > t {
-			seatPrice = t
-		}
 	}
 
-	w.metrics.SeatPrice.Set(seatPrice)
-
 	for _, v := range validators.NextValidators {
 		w.metrics.NextValidatorStake.
 			WithLabelValues(v.AccountId, v.PublicKey, labelEpochStartHeight, w.isTracked(v.AccountId)).
@@ -192,16 +379,134 @@ func (w *Watcher) collectValidators(ctx context.Context) (near.ValidatorsRespons
 	}
 
 	for _, v := range validators.PrevEpochKickOut {
-		reason, _ := json.Marshal(v.Reason)
+		reason := string(v.Reason.Type)
 
 		w.metrics.PrevEpochKickout.
-			WithLabelValues(v.AccountId, string(reason), labelEpochStartHeight, w.isTracked(v.AccountId)).
+			WithLabelValues(v.AccountId, reason, labelEpochStartHeight, w.isTracked(v.AccountId)).
 			Set(1)
+
+		switch v.Reason.Type {
+		case near.KickoutReasonNotEnoughBlocks, near.KickoutReasonNotEnoughChunks, near.KickoutReasonNotEnoughChunkEndorsements:
+			w.metrics.PrevEpochKickoutExpected.WithLabelValues(v.AccountId, reason).Set(float64(v.Reason.Expected))
+			w.metrics.PrevEpochKickoutProduced.WithLabelValues(v.AccountId, reason).Set(float64(v.Reason.Produced))
+		case near.KickoutReasonNotEnoughStake:
+			w.metrics.PrevEpochKickoutStake.WithLabelValues(v.AccountId, reason).Set(v.Reason.Stake.Div(yoctoUnit).InexactFloat64())
+			w.metrics.PrevEpochKickoutStakeThreshold.WithLabelValues(v.AccountId, reason).Set(v.Reason.Threshold.Div(yoctoUnit).InexactFloat64())
+		case near.KickoutReasonProtocolVersionTooOld:
+			w.metrics.PrevEpochKickoutProtocolVersion.WithLabelValues(v.AccountId, reason).Set(float64(v.Reason.Version))
+		}
 	}
 
 	return validators, nil
 }
 
+func (w *Watcher) collectKickoutForecast(status near.StatusResponse, validators near.ValidatorsResponse, config near.ProtocolConfigResponse) {
+	logrus.Debug("collect kickout forecast")
+
+	w.metrics.ValidatorBlocksTolerableMisses.Reset()
+	w.metrics.ValidatorChunksTolerableMisses.Reset()
+	w.metrics.ValidatorBlocksProjectedUptime.Reset()
+	w.metrics.ValidatorChunksProjectedUptime.Reset()
+
+	var (
+		blocksThreshold, chunksThreshold = kickoutThresholds(config)
+		elapsed, remaining               = epochProgress(validators, config, status.SyncInfo.LatestBlockHeight)
+	)
+
+	w.metrics.EpochRemainingBlocks.Set(float64(remaining))
+
+	labelEpochStartHeight := strconv.FormatInt(validators.EpochStartHeight, 10)
+
+	for _, v := range validators.CurrentValidators {
+		if w.isTracked(v.AccountId) != "1" {
+			continue
+		}
+
+		labels := []string{v.AccountId, v.PublicKey, labelEpochStartHeight, "1"}
+
+		blocks := forecastKickout(v.NumProducedBlocks, v.NumExpectedBlocks, elapsed, remaining, blocksThreshold)
+		chunks := forecastKickout(v.NumProducedChunks, v.NumExpectedChunks, elapsed, remaining, chunksThreshold)
+
+		w.metrics.ValidatorBlocksTolerableMisses.WithLabelValues(labels...).Set(float64(blocks.TolerableMisses))
+		w.metrics.ValidatorChunksTolerableMisses.WithLabelValues(labels...).Set(float64(chunks.TolerableMisses))
+		w.metrics.ValidatorBlocksProjectedUptime.WithLabelValues(labels...).Set(blocks.ProjectedUptime)
+		w.metrics.ValidatorChunksProjectedUptime.WithLabelValues(labels...).Set(chunks.ProjectedUptime)
+
+		if blocks.TolerableMisses < 0 || chunks.TolerableMisses < 0 {
+			logrus.WithFields(logrus.Fields{
+				"account_id":              v.AccountId,
+				"blocks_tolerable_misses": blocks.TolerableMisses,
+				"chunks_tolerable_misses": chunks.TolerableMisses,
+			}).Warn("validator will be kicked out at the end of the epoch")
+		}
+	}
+}
+
+func (w *Watcher) collectSeatPrice(validators near.ValidatorsResponse, config near.ProtocolConfigResponse) {
+	logrus.Debug("collect seat price")
+
+	w.metrics.ValidatorSeatPriceMargin.Reset()
+	w.seatPrice = nil
+
+	var (
+		current   = make(map[string]decimal.Decimal)
+		next      = make(map[string]decimal.Decimal)
+		proposals = make(map[string]decimal.Decimal)
+	)
+	for _, v := range validators.CurrentValidators {
+		current[v.AccountId] = v.Stake
+	}
+	for _, v := range validators.NextValidators {
+		next[v.AccountId] = v.Stake
+		proposals[v.AccountId] = v.Stake
+	}
+	// Proposals are applied on top of the next validators for the epoch after
+	// next, a proposal with no stake being an unstake.
+	for _, v := range validators.CurrentProposals {
+		if v.Stake.IsZero() {
+			delete(proposals, v.AccountId)
+		} else {
+			proposals[v.AccountId] = v.Stake
+		}
+	}
+
+	for _, epoch := range []struct {
+		name   string
+		stakes map[string]decimal.Decimal
+		gauge  prometheus.Gauge
+	}{
+		{"current", current, w.metrics.SeatPrice},
+		{"next", next, w.metrics.NextSeatPrice},
+		{"proposals", proposals, w.metrics.ProposalsSeatPrice},
+	} {
+		stakes := make([]decimal.Decimal, 0, len(epoch.stakes))
+		for _, stake := range epoch.stakes {
+			stakes = append(stakes, stake)
+		}
+
+		seatPrice, err := near.FindSeatPrice(stakes, config.NumSeats(), config.MinimumStakeRatio, config.ProtocolVersion)
+		if err != nil {
+			logrus.WithError(err).Warnf("failed to compute %s seat price", epoch.name)
+			continue
+		}
+		if epoch.name == "current" {
+			w.seatPrice = &seatPrice
+		}
+
+		epoch.gauge.Set(seatPrice.Div(yoctoUnit).InexactFloat64())
+
+		for _, account := range w.config.TrackedAccounts {
+			stake, ok := epoch.stakes[account]
+			if !ok {
+				continue
+			}
+			w.metrics.ValidatorSeatPriceMargin.
+				WithLabelValues(account, epoch.name).
+				Set(stake.Sub(seatPrice).Div(yoctoUnit).InexactFloat64())
+		}
+	}
+}
+
 func (w *Watcher) isTracked(accountId string) string {
 	for _, t := range w.config.TrackedAccounts {
 		if accountId == t {
@@ -211,7 +516,13 @@ func (w *Watcher) isTracked(accountId string) string {
 	return "0"
 }
 
-func (w *Watcher) printStatusLine(status near.StatusResponse, validators near.ValidatorsResponse) {
+func (w *Watcher) printStatusLine(status near.StatusResponse, validators near.ValidatorsResponse, config near.ProtocolConfigResponse) {
+	var (
+		blocksThreshold, chunksThreshold = kickoutThresholds(config)
+		endorsementsThreshold            = endorsementsKickoutThreshold(config)
+		elapsed, remaining               = epochProgress(validators, config, status.SyncInfo.LatestBlockHeight)
+	)
+
 	validatorStatus := make([]string, 0)
 	for _, account := range w.config.TrackedAccounts {
 		for _, validator := range validators.CurrentValidators {
@@ -220,36 +531,51 @@ func (w *Watcher) printStatusLine(status near.StatusResponse, validators near.Va
 			}
 
 			var (
-				status               = "✅"
-				uptimeBlocks float64 = 100
-				uptimeChunks float64 = 100
+				status                     = "✅"
+				uptimeBlocks, uptimeChunks = uptime(validator)
+				uptimeEndorsements         = validator.EndorsementsUptime()
+				blocks                     = forecastKickout(validator.NumProducedBlocks, validator.NumExpectedBlocks, elapsed, remaining, blocksThreshold)
+				chunks                     = forecastKickout(validator.NumProducedChunks, validator.NumExpectedChunks, elapsed, remaining, chunksThreshold)
 			)
 
-			if validator.NumExpectedBlocks > 0 {
-				uptimeBlocks = 100 * float64(validator.NumProducedBlocks) / float64(validator.NumExpectedBlocks)
+			if uptimeBlocks < float64(blocksThreshold) || uptimeChunks < float64(chunksThreshold) {
+				status = "⚠️"
 			}
-			if validator.NumExpectedChunks > 0 {
-				uptimeChunks = 100 * float64(validator.NumProducedChunks) / float64(validator.NumExpectedChunks)
+			if validator.IsChunkValidatorOnly() && uptimeEndorsements < float64(endorsementsThreshold) {
+				status = "⚠️"
 			}
-			if uptimeBlocks < 90 || uptimeChunks < 90 {
+			if blocks.TolerableMisses < 0 || chunks.TolerableMisses < 0 {
 				status = "❌"
 			}
 
+			// Chunk validators only have nothing but endorsements to show
+			uptimes := fmt.Sprintf("%s%%, %s%%, %s%%",
+				prettyPrintFloat(uptimeBlocks),
+				prettyPrintFloat(uptimeChunks),
+				prettyPrintFloat(uptimeEndorsements),
+			)
+			if validator.IsChunkValidatorOnly() {
+				uptimes = fmt.Sprintf("endorsements %s%%", prettyPrintFloat(uptimeEndorsements))
+			}
+
 			validatorStatus = append(validatorStatus,
-				fmt.Sprintf("%s %s (%s%%, %s%%)",
+				fmt.Sprintf("%s %s (%s)",
 					status,
-					prettyPrintAccountID(validator.AccountId),
-					prettyPrintFloat(uptimeBlocks),
-					prettyPrintFloat(uptimeChunks),
+					w.config.displayName(validator.AccountId),
+					uptimes,
 				),
 			)
 		}
 	}
 
-	fmt.Fprintln(
-		w.config.Writer,
+	fields := []interface{}{
 		color.YellowString(fmt.Sprintf("#%d (%d)", status.SyncInfo.LatestBlockHeight, validators.EpochHeight)),
 		color.CyanString(fmt.Sprintf("%d validators", len(validators.CurrentValidators))),
 		strings.Join(validatorStatus, " "),
-	)
+	}
+	if w.config.Network != "" {
+		fields = append([]interface{}{color.MagentaString("[%s]", w.config.Network)}, fields...)
+	}
+
+	fmt.Fprintln(w.config.Writer, fields...)
 }
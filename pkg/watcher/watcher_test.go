@@ -1,8 +1,15 @@
 package watcher
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/kilnfi/near-validator-watcher/pkg/metrics"
 	"github.com/kilnfi/near-validator-watcher/pkg/near"
@@ -17,7 +24,7 @@ func TestWatcher(t *testing.T) {
 		ctx     = context.Background()
 		resp    = testutils.ExpectedResponse{}
 		server  = testutils.NewServer(&resp)
-		client  = near.NewClient(server.URL)
+		client  = near.NewClient([]string{server.URL})
 		metrics = metrics.New("near_validator_watcher")
 		watcher = New(client, metrics, &Config{
 			TrackedAccounts: []string{"kiln.pool.f863973.m0"},
@@ -92,7 +99,7 @@ func TestWatcher(t *testing.T) {
 		_, err := watcher.collectStatus(ctx)
 		require.NoError(t, err)
 
-		assert.Equal(t, true, watcher.IsSynced())
+		assert.Equal(t, float64(0), testutil.ToFloat64(metrics.SyncingDesc))
 
 		assert.Equal(t, float64(142259035), testutil.ToFloat64(metrics.BlockNumber))
 		assert.NotEqual(t, float64(0), testutil.ToFloat64(metrics.ChainID.WithLabelValues("testnet")))
@@ -203,15 +210,29 @@ func TestWatcher(t *testing.T) {
 										"num_expected_chunks_per_shard": [
 												392
 										],
+										"num_expected_endorsements": 1500,
+										"num_expected_endorsements_per_shard": [
+												800,
+												700
+										],
 										"num_produced_blocks": 91,
 										"num_produced_chunks": 391,
 										"num_produced_chunks_per_shard": [
 												391
 										],
+										"num_produced_endorsements": 1490,
+										"num_produced_endorsements_per_shard": [
+												795,
+												695
+										],
 										"public_key": "ed25519:Bq8fe1eUgDRexX2CYDMhMMQBiN13j8vTAVFyTNhEfh1W",
 										"shards": [
 												0
 										],
+										"shards_endorsed": [
+												0,
+												1
+										],
 										"stake": "6736422258840329637507414885764"
 								},
 								{
@@ -302,11 +323,39 @@ func TestWatcher(t *testing.T) {
 				}
 		}`)
 
-		_, err := watcher.collectValidators(ctx)
+		validators, err := watcher.collectValidators(ctx)
 		require.NoError(t, err)
 
 		assert.Equal(t, float64(142256359), testutil.ToFloat64(metrics.EpochStartHeight))
+
+		// Seat price, chunk validators seats count with stateless validation
+		watcher.collectSeatPrice(validators, near.ProtocolConfigResponse{
+			NumBlockProducerSeats:  2,
+			NumChunkProducerSeats:  3,
+			NumChunkValidatorSeats: 5,
+			MinimumStakeRatio:      []int64{1, 6250},
+			ProtocolVersion:        63,
+		})
 		assert.Equal(t, float64(5048850.744401447176504014136424), testutil.ToFloat64(metrics.SeatPrice))
+		require.NotNil(t, watcher.seatPrice)
+		assert.Equal(t, float64(5048850.744401447176504014136424), watcher.seatPrice.Div(yoctoUnit).InexactFloat64())
+		assert.Equal(t, float64(5050537.292472590301154723011599), testutil.ToFloat64(metrics.NextSeatPrice))
+		assert.Equal(t, float64(6739683.523640860052948489127117), testutil.ToFloat64(metrics.ProposalsSeatPrice))
+		assert.Equal(t, 3, testutil.CollectAndCount(metrics.ValidatorSeatPriceMargin))
+		assert.Equal(t, float64(1687571.514438882461003400749339), testutil.ToFloat64(metrics.ValidatorSeatPriceMargin.WithLabelValues(
+			"kiln.pool.f863973.m0",
+			"current",
+		)))
+		assert.Equal(t, float64(1689146.230965942656477566115517), testutil.ToFloat64(metrics.ValidatorSeatPriceMargin.WithLabelValues(
+			"kiln.pool.f863973.m0",
+			"next",
+		)))
+
+		// Next epoch
+		watcher.collectNextEpoch(validators)
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.ValidatorInNextEpoch.WithLabelValues("kiln.pool.f863973.m0")))
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.ValidatorProposalPending.WithLabelValues("kiln.pool.f863973.m0")))
+		assert.InDelta(t, 3261.2645982, testutil.ToFloat64(metrics.ValidatorNextStakeDelta.WithLabelValues("kiln.pool.f863973.m0")), 1e-6)
 
 		// ValidatorRank
 		assert.Equal(t, 5, testutil.CollectAndCount(metrics.ValidatorRank))
@@ -383,6 +432,36 @@ func TestWatcher(t *testing.T) {
 			"1",
 		)))
 
+		// Per shard stats
+		assert.Equal(t, 6, testutil.CollectAndCount(metrics.ValidatorShardProducedChunks))
+		assert.Equal(t, float64(391), testutil.ToFloat64(metrics.ValidatorShardProducedChunks.WithLabelValues(
+			"kiln.pool.f863973.m0",
+			`ed25519:Bq8fe1eUgDRexX2CYDMhMMQBiN13j8vTAVFyTNhEfh1W`,
+			"142256359",
+			"1",
+			"0",
+		)))
+		assert.Equal(t, float64(0), testutil.ToFloat64(metrics.ValidatorShardExpectedChunks.WithLabelValues(
+			"kiln.pool.f863973.m0",
+			`ed25519:Bq8fe1eUgDRexX2CYDMhMMQBiN13j8vTAVFyTNhEfh1W`,
+			"142256359",
+			"1",
+			"1",
+		)))
+		assert.Equal(t, float64(695), testutil.ToFloat64(metrics.ValidatorShardProducedEndorsements.WithLabelValues(
+			"kiln.pool.f863973.m0",
+			`ed25519:Bq8fe1eUgDRexX2CYDMhMMQBiN13j8vTAVFyTNhEfh1W`,
+			"142256359",
+			"1",
+			"1",
+		)))
+		assert.Equal(t, float64(0), testutil.ToFloat64(metrics.ValidatorChunkValidatorOnly.WithLabelValues(
+			"kiln.pool.f863973.m0",
+			`ed25519:Bq8fe1eUgDRexX2CYDMhMMQBiN13j8vTAVFyTNhEfh1W`,
+			"142256359",
+			"1",
+		)))
+
 		// Slashed
 		assert.Equal(t, 5, testutil.CollectAndCount(metrics.ValidatorSlashed))
 		assert.Equal(t, float64(0), testutil.ToFloat64(metrics.ValidatorSlashed.WithLabelValues(
@@ -419,15 +498,103 @@ func TestWatcher(t *testing.T) {
 		assert.Equal(t, 2, testutil.CollectAndCount(metrics.PrevEpochKickout))
 		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.PrevEpochKickout.WithLabelValues(
 			"example1.pool.f863973.m0",
-			`{"NotEnoughBlocks":{"expected":16,"produced":0}}`,
+			"NotEnoughBlocks",
 			"142256359",
 			"0",
 		)))
 		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.PrevEpochKickout.WithLabelValues(
 			"example2.pool.f863973.m0",
-			`{"NotEnoughBlocks":{"expected":63,"produced":0}}`,
+			"NotEnoughBlocks",
 			"142256359",
 			"0",
 		)))
+		assert.Equal(t, float64(63), testutil.ToFloat64(metrics.PrevEpochKickoutExpected.WithLabelValues(
+			"example2.pool.f863973.m0",
+			"NotEnoughBlocks",
+		)))
+		assert.Equal(t, float64(0), testutil.ToFloat64(metrics.PrevEpochKickoutProduced.WithLabelValues(
+			"example2.pool.f863973.m0",
+			"NotEnoughBlocks",
+		)))
 	})
 }
+
+// node serves the requests of a collection cycle: block from the chain, query
+// from the staking pool contracts, and the other methods from their result,
+// failing with a request error when it is missing.
+type node struct {
+	chain     *blockChain
+	contracts *stakingPoolContract
+	results   map[string]string
+}
+
+func (n node) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	var payload struct {
+		Method string `json:"method"`
+	}
+	_ = json.Unmarshal(body, &payload)
+
+	switch payload.Method {
+	case "block":
+		n.chain.ServeHTTP(res, req)
+	case "query":
+		n.contracts.ServeHTTP(res, req)
+	default:
+		result, ok := n.results[payload.Method]
+		if !ok {
+			_, _ = res.Write([]byte(`{"jsonrpc": "2.0", "id": "dontcare", "error": {"name": "REQUEST_VALIDATION_ERROR", "code": -32600, "cause": {"name": "METHOD_NOT_FOUND"}}}`))
+			return
+		}
+		_, _ = fmt.Fprintf(res, `{"jsonrpc": "2.0", "id": "dontcare", "result": %s}`, result)
+	}
+}
+
+func TestCollectDataPartialFailure(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		contracts = newStakingPoolContract(map[string]map[string]string{"kiln.poolv1.near": stakingPoolResults()})
+		server    = httptest.NewServer(node{
+			chain: &blockChain{
+				head:    100,
+				authors: map[uint64]string{100: "kiln.poolv1.near"},
+				prev:    map[uint64]uint64{100: 99},
+			},
+			contracts: contracts,
+			results: map[string]string{
+				"status": `{"chain_id": "mainnet", "sync_info": {
+					"latest_block_height": 100,
+					"latest_block_hash": "hash100",
+					"latest_block_time": "2023-10-18T13:35:36Z"
+				}}`,
+				"validators": `{"epoch_height": 10, "epoch_start_height": 50, "current_validators": [
+					{"account_id": "kiln.poolv1.near", "stake": "1000", "num_produced_blocks": 9, "num_expected_blocks": 10}
+				]}`,
+			},
+		})
+		metrics = metrics.New("near_validator_watcher")
+		watcher = New(near.NewClient([]string{server.URL}), metrics, &Config{
+			Writer:          io.Discard,
+			TrackedAccounts: []string{"kiln.poolv1.near", "other.poolv1.near"},
+			RefreshRate:     time.Second,
+		})
+	)
+	defer server.Close()
+
+	// The protocol config can't be fetched and the other tracked account isn't
+	// a staking pool, the other collectors still update their metrics
+	err := watcher.collectData(ctx)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, near.ErrRequestValidation)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.CollectionErrors.WithLabelValues(collectorProtocolConfig)))
+	assert.Equal(t, float64(0), testutil.ToFloat64(metrics.CollectionErrors.WithLabelValues(collectorStakingPools)))
+	assert.Equal(t, float64(0), testutil.ToFloat64(metrics.EpochLength))
+
+	assert.Equal(t, float64(100), testutil.ToFloat64(metrics.BlockNumber))
+	assert.Equal(t, float64(9), testutil.ToFloat64(metrics.ValidatorProducedBlocks.WithLabelValues("kiln.poolv1.near", "", "50", "1")))
+	assert.Equal(t, float64(42), testutil.ToFloat64(metrics.StakingPoolAccounts.WithLabelValues("kiln.poolv1.near")))
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.StakingPoolAccounts))
+}